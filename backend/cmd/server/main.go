@@ -2,8 +2,13 @@ package main
 
 import (
 	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	apphttp "github.com/DevloperAmanSingh/secret-scanning/internal/http"
+	"github.com/DevloperAmanSingh/secret-scanning/internal/scanner"
 	"github.com/DevloperAmanSingh/secret-scanning/internal/storage"
 )
 
@@ -15,7 +20,63 @@ func main() {
 		log.Fatalf("db migrate error: %v", err)
 	}
 
+	scanner.Base64EntropyThreshold = envFloat("SCANNER_ENTROPY_BASE64", scanner.Base64EntropyThreshold)
+	scanner.HexEntropyThreshold = envFloat("SCANNER_ENTROPY_HEX", scanner.HexEntropyThreshold)
+	scanner.DecodeDepth = int(envFloat("SCANNER_DECODE_DEPTH", float64(scanner.DecodeDepth)))
+	scanner.Workers = int(envFloat("SCANNER_WORKERS", float64(scanner.Workers)))
+	scanner.MinifiedLineLength = int(envFloat("SCANNER_MINIFIED_LINE_LENGTH", float64(scanner.MinifiedLineLength)))
+	scanner.ScanBudget = time.Duration(envFloat("SCANNER_TIME_BUDGET_SECONDS", scanner.ScanBudget.Seconds()) * float64(time.Second))
+	apphttp.ScanTimeout = time.Duration(envFloat("SCAN_TIMEOUT_SECONDS", apphttp.ScanTimeout.Seconds()) * float64(time.Second))
+	if v := os.Getenv("SCANNER_INCLUDE_PATHS"); v != "" {
+		apphttp.IncludePaths = strings.Split(v, ",")
+	}
+	if v := os.Getenv("SCANNER_EXCLUDE_PATHS"); v != "" {
+		apphttp.ExcludePaths = append(append([]string{}, scanner.DefaultExcludes...), strings.Split(v, ",")...)
+	}
+	if _, err := scanner.NewPathFilter(apphttp.IncludePaths, apphttp.ExcludePaths); err != nil {
+		log.Fatalf("scanner path filters: %v", err)
+	}
+	custom := []*scanner.Rule{}
+	if path := os.Getenv("GITLEAKS_CONFIG"); path != "" {
+		rules, err := scanner.ReadGitleaksConfig(path)
+		if err != nil {
+			log.Fatalf("scanner rules error: %v", err)
+		}
+		log.Printf("scanner: imported %d gitleaks rules from %s", len(rules), path)
+		custom = append(custom, rules...)
+	}
+	if path := os.Getenv("SCANNER_RULES_FILE"); path != "" {
+		rules, err := scanner.ReadRulesFile(path)
+		if err != nil {
+			log.Fatalf("scanner rules error: %v", err)
+		}
+		log.Printf("scanner: loaded %d custom rules from %s", len(rules), path)
+		custom = append(custom, rules...)
+	}
+	scanner.SetRules(custom)
+	if ids := os.Getenv("SCANNER_DISABLED_RULES"); ids != "" {
+		if err := scanner.DisableRules(strings.Split(ids, ",")); err != nil {
+			log.Fatalf("SCANNER_DISABLED_RULES: %v", err)
+		}
+	}
+	log.Printf("scanner: built-in rule pack %s", scanner.RulePackVersion)
+	if err := storage.BackfillSeverity(scanner.Severities()); err != nil {
+		log.Printf("severity backfill failed: %v", err)
+	}
+
 	app := apphttp.SetupRoutes()
 	log.Println("🚀 Backend API running on :8080")
 	log.Fatal(app.Listen(":8080"))
 }
+
+func envFloat(key string, def float64) float64 {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		log.Fatalf("invalid %s: %v", key, err)
+	}
+	return f
+}
@@ -1,8 +1,11 @@
 package http
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
+	"io"
 	"log"
 	"strings"
 	"time"
@@ -12,6 +15,18 @@ import (
 	"github.com/DevloperAmanSingh/secret-scanning/internal/storage"
 
 	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// ScanTimeout bounds how long a single request may spend scanning, across
+// all files or bulk items it carries.
+var ScanTimeout = 60 * time.Second
+
+// IncludePaths and ExcludePaths are the global gitignore-style path filters.
+// A repo's stored filter is combined with them by pathFilter.
+var (
+	IncludePaths []string
+	ExcludePaths = scanner.DefaultExcludes
 )
 
 type ScanRequest struct {
@@ -24,12 +39,18 @@ type ScanRequest struct {
 }
 
 type ScanResponse struct {
-	Success    bool             `json:"success"`
-	Created    int              `json:"created"`
-	Resolved   int              `json:"resolved"`
-	Duplicates int              `json:"duplicates"`
-	Issues     []map[string]any `json:"issues"`
-	Errors     []string         `json:"errors"`
+	Success     bool `json:"success"`
+	Created     int  `json:"created"`
+	Resolved    int  `json:"resolved"`
+	Duplicates  int  `json:"duplicates"`
+	Allowlisted int  `json:"allowlisted"`
+	Disabled    int  `json:"disabled"`
+	// SkippedFiles counts files that were not scanned; SkipReason says why
+	// when the request carried a single file.
+	SkippedFiles int              `json:"skippedFiles"`
+	SkipReason   string           `json:"skipReason,omitempty"`
+	Issues       []map[string]any `json:"issues"`
+	Errors       []string         `json:"errors"`
 }
 
 type ResolveResponse struct {
@@ -60,6 +81,30 @@ func scanHandler(c *fiber.Ctx) error {
 	}
 	log.Printf("/scan received: source=%s payload_len=%d", source, len(payload))
 
+	if reason := pathFilter(req.Repo).Skip(req.File); reason != "" {
+		log.Printf("/scan skipped: file=%s reason=%s", req.File, reason)
+		return c.JSON(ScanResponse{SkippedFiles: 1, SkipReason: reason, Issues: []map[string]any{}, Errors: []string{}})
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), ScanTimeout)
+	defer cancel()
+	resp, err := scanAndCreateIssues(ctx, payload, scanMetadata(req), req)
+	if err != nil {
+		return c.Status(504).JSON(fiber.Map{"error": scanErrorMessage(err)})
+	}
+	return c.JSON(resp)
+}
+
+// scanErrorMessage describes a scan that was cut short, either by the
+// request timeout or by the scanner's per-scan budget.
+func scanErrorMessage(err error) string {
+	if errors.Is(err, scanner.ErrScanBudget) {
+		return "scan exceeded its time budget"
+	}
+	return "scan timed out"
+}
+
+func scanMetadata(req ScanRequest) string {
 	metaLines := []string{}
 	if req.Repo != "" {
 		metaLines = append(metaLines, "Repository: "+req.Repo)
@@ -73,9 +118,103 @@ func scanHandler(c *fiber.Ctx) error {
 	if req.File != "" {
 		metaLines = append(metaLines, "File: "+req.File)
 	}
-	metadata := strings.Join(metaLines, "\n")
+	return strings.Join(metaLines, "\n")
+}
+
+type SkippedFile struct {
+	File   string `json:"file"`
+	Reason string `json:"reason"`
+}
+
+type FileScanResponse struct {
+	ScanResponse
+	Files   int           `json:"files"`
+	Skipped []SkippedFile `json:"skipped"`
+}
+
+// scanFileHandler scans a multipart upload (field "file"). Archives are
+// unpacked and each member is scanned as its own file, named
+// "upload.zip!/path/inside", so tickets point at the exact member.
+func scanFileHandler(c *fiber.Ctx) error {
+	fh, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "missing file"})
+	}
+	f, err := fh.Open()
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "invalid file"})
+	}
+	data, err := io.ReadAll(f)
+	f.Close()
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "invalid file"})
+	}
+	base := ScanRequest{Repo: c.FormValue("repo"), Commit: c.FormValue("commit"), Channel: c.FormValue("channel")}
+	log.Printf("/scan/file received: name=%s size=%d", fh.Filename, len(data))
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), ScanTimeout)
+	defer cancel()
+	resp := FileScanResponse{ScanResponse: ScanResponse{Issues: []map[string]any{}, Errors: []string{}}, Skipped: []SkippedFile{}}
+	filter := pathFilter(base.Repo)
+	scanOne := func(name string, content string) error {
+		if reason := filter.Skip(name); reason != "" {
+			resp.Skipped = append(resp.Skipped, SkippedFile{File: name, Reason: reason})
+			return nil
+		}
+		req := base
+		req.File = name
+		req.Content = content
+		r, err := scanAndCreateIssues(ctx, content, scanMetadata(req), req)
+		if errors.Is(err, scanner.ErrScanBudget) {
+			resp.Skipped = append(resp.Skipped, SkippedFile{File: name, Reason: "time budget exceeded"})
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if r.SkipReason != "" {
+			resp.Skipped = append(resp.Skipped, SkippedFile{File: name, Reason: r.SkipReason})
+			return nil
+		}
+		resp.Files++
+		resp.Created += r.Created
+		resp.Resolved += r.Resolved
+		resp.Duplicates += r.Duplicates
+		resp.Allowlisted += r.Allowlisted
+		resp.Disabled += r.Disabled
+		resp.Issues = append(resp.Issues, r.Issues...)
+		resp.Errors = append(resp.Errors, r.Errors...)
+		return nil
+	}
 
-	return scanAndCreateIssues(c, payload, metadata, req)
+	switch {
+	case scanner.IsArchive(fh.Filename, data):
+		err = scanner.WalkArchive(fh.Filename, data, scanner.DefaultArchiveLimits, func(e scanner.ArchiveEntry) error {
+			if e.Skipped != "" {
+				resp.Skipped = append(resp.Skipped, SkippedFile{File: e.Path, Reason: e.Skipped})
+				return nil
+			}
+			return scanOne(e.Path, string(e.Content))
+		})
+		if errors.Is(err, context.DeadlineExceeded) {
+			return c.Status(504).JSON(fiber.Map{"error": "scan timed out"})
+		}
+		if errors.Is(err, scanner.ErrArchiveLimit) {
+			return c.Status(413).JSON(fiber.Map{"error": err.Error()})
+		}
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+	case scanner.IsBinary(data):
+		resp.Skipped = append(resp.Skipped, SkippedFile{File: fh.Filename, Reason: "binary"})
+	default:
+		if err := scanOne(fh.Filename, string(data)); err != nil {
+			return c.Status(504).JSON(fiber.Map{"error": scanErrorMessage(err)})
+		}
+	}
+	resp.SkippedFiles = len(resp.Skipped)
+	resp.Success = resp.Created > 0 && len(resp.Errors) == 0
+	return c.JSON(resp)
 }
 
 type BulkScanItem struct {
@@ -92,11 +231,15 @@ type BulkScanRequest struct {
 }
 
 type BulkScanResult struct {
-	Index      int    `json:"index"`
-	Created    int    `json:"created"`
-	Resolved   int    `json:"resolved"`
-	Duplicates int    `json:"duplicates"`
-	Error      string `json:"error,omitempty"`
+	Index       int `json:"index"`
+	Created     int `json:"created"`
+	Resolved    int `json:"resolved"`
+	Duplicates  int `json:"duplicates"`
+	Allowlisted int `json:"allowlisted"`
+	Disabled    int `json:"disabled"`
+	// Skipped is why the item was not scanned, if it wasn't.
+	Skipped string `json:"skipped,omitempty"`
+	Error   string `json:"error,omitempty"`
 }
 
 func scanBulkHandler(c *fiber.Ctx) error {
@@ -104,8 +247,54 @@ func scanBulkHandler(c *fiber.Ctx) error {
 	if err := c.BodyParser(&req); err != nil || len(req.Items) == 0 {
 		return c.Status(400).JSON(fiber.Map{"error": "invalid request"})
 	}
+	// Scan every item on the worker pool first; tickets are then created
+	// in item order so results don't depend on scheduling.
+	ctx, cancel := context.WithTimeout(c.UserContext(), ScanTimeout)
+	defer cancel()
+	skipped := make([]string, len(req.Items))
+	filters := map[string]*scanner.PathFilter{}
+	for i, item := range req.Items {
+		f, ok := filters[item.Repo]
+		if !ok {
+			f = pathFilter(item.Repo)
+			filters[item.Repo] = f
+		}
+		skipped[i] = f.Skip(item.File)
+	}
+	reports := make([]scanner.Report, len(req.Items))
+	errs := make([]error, len(req.Items))
+	err := scanner.Parallel(ctx, len(req.Items), func(i int) {
+		if skipped[i] != "" {
+			return
+		}
+		item := req.Items[i]
+		payload := item.Content
+		if payload == "" {
+			payload = item.Text
+		}
+		reports[i], errs[i] = scanner.ScanReportContext(ctx, item.File, payload)
+	})
+	if err == nil {
+		err = errors.Join(errs...)
+	}
+	if err != nil {
+		return c.Status(504).JSON(fiber.Map{"error": scanErrorMessage(err)})
+	}
+
+	for i, report := range reports {
+		if report.Kind == scanner.ContentBinary {
+			skipped[i] = report.KindReason
+		}
+	}
+
 	results := make([]BulkScanResult, 0, len(req.Items))
+	skippedCount := 0
 	for i, item := range req.Items {
+		if skipped[i] != "" {
+			results = append(results, BulkScanResult{Index: i, Skipped: skipped[i]})
+			skippedCount++
+			continue
+		}
 		r := ScanRequest{Content: item.Content, Text: item.Text, Repo: item.Repo, Commit: item.Commit, Channel: item.Channel, File: item.File}
 		payload := r.Content
 		if payload == "" {
@@ -126,8 +315,9 @@ func scanBulkHandler(c *fiber.Ctx) error {
 		}
 		metadata := strings.Join(meta, "\n")
 
-		findings := scanner.Scan(payload)
-		_ = autoResolveIssues(payload, r)
+		report := scanner.ApplyOverrides(reports[i], ruleOverrides(r.Repo, r.Channel))
+		findings := report.Findings
+		_ = autoResolveIssues(findings, r)
 
 		resolvedCount := 0
 		q := storage.DB.Where("status = ?", "resolved")
@@ -160,27 +350,36 @@ func scanBulkHandler(c *fiber.Ctx) error {
 				duplicates++
 				continue
 			}
-			issueID, err := linear.CreateIssue(f.Type, metadata, time.Now())
+			issueID, err := linear.CreateIssue(f, metadata, time.Now())
 			if err != nil || issueID == "" {
 				continue
 			}
-			issue := storage.Issue{ID: issueID, Type: f.Type, Status: "active", Repo: r.Repo, Commit: r.Commit, Channel: r.Channel, File: r.File, Content: payload, Fingerprint: fp}
+			issue := storage.Issue{ID: issueID, Type: f.Type, Severity: f.Severity, Confidence: f.Confidence, Status: "active", Repo: r.Repo, Commit: r.Commit, Channel: r.Channel, File: r.File, Line: f.Line, Column: f.Column, StartOffset: f.Start, EndOffset: f.End, Snippet: f.Snippet, Content: payload, Fingerprint: fp}
 			if err := storage.DB.Create(&issue).Error; err == nil {
 				created++
 			}
 		}
-		results = append(results, BulkScanResult{Index: i, Created: created, Resolved: resolvedCount, Duplicates: duplicates})
+		results = append(results, BulkScanResult{Index: i, Created: created, Resolved: resolvedCount, Duplicates: duplicates, Allowlisted: report.Allowlisted, Disabled: report.Disabled})
 	}
-	return c.JSON(fiber.Map{"results": results})
+	return c.JSON(fiber.Map{"results": results, "skipped": skippedCount})
 }
 
-func scanAndCreateIssues(c *fiber.Ctx, payload string, metadata string, req ScanRequest) error {
-	findings := scanner.Scan(payload)
-	log.Printf("/scan findings: count=%d", len(findings))
+func scanAndCreateIssues(ctx context.Context, payload string, metadata string, req ScanRequest) (ScanResponse, error) {
+	report, err := scanner.ScanReportContext(ctx, req.File, payload)
+	if err != nil {
+		return ScanResponse{}, err
+	}
+	if report.Kind == scanner.ContentBinary {
+		log.Printf("/scan skipped: file=%s reason=%s", req.File, report.KindReason)
+		return ScanResponse{SkippedFiles: 1, SkipReason: report.KindReason, Issues: []map[string]any{}, Errors: []string{}}, nil
+	}
+	report = scanner.ApplyOverrides(report, ruleOverrides(req.Repo, req.Channel))
+	findings := report.Findings
+	log.Printf("/scan findings: count=%d allowlisted=%d suppressed=%d disabled=%d", len(findings), report.Allowlisted, len(report.Suppressed), report.Disabled)
 
 	// First, check for auto-resolution: close issues that are no longer detected
 	resolvedCount := 0
-	if err := autoResolveIssues(payload, req); err != nil {
+	if err := autoResolveIssues(findings, req); err != nil {
 		log.Printf("auto-resolve failed: %v", err)
 	} else {
 		// Count resolved issues for this scan
@@ -227,16 +426,17 @@ func scanAndCreateIssues(c *fiber.Ctx, payload string, metadata string, req Scan
 		if result.Error == nil {
 			log.Printf("duplicate issue detected: type=%s fp=%s, skipping creation", f.Type, fp)
 			respIssues = append(respIssues, map[string]any{
-				"id":      existingIssue.ID,
-				"type":    f.Type,
-				"status":  "duplicate",
-				"message": "Issue already exists",
+				"id":       existingIssue.ID,
+				"type":     f.Type,
+				"severity": f.Severity,
+				"status":   "duplicate",
+				"message":  "Issue already exists",
 			})
 			duplicates++
 			continue
 		}
 
-		issueID, err := linear.CreateIssue(f.Type, metadata, time.Now())
+		issueID, err := linear.CreateIssue(f, metadata, time.Now())
 		if err != nil {
 			log.Printf("linear create issue failed: type=%s err=%v", f.Type, err)
 			errs = append(errs, err.Error())
@@ -249,11 +449,18 @@ func scanAndCreateIssues(c *fiber.Ctx, payload string, metadata string, req Scan
 		issue := storage.Issue{
 			ID:          issueID,
 			Type:        f.Type,
+			Severity:    f.Severity,
+			Confidence:  f.Confidence,
 			Status:      "active",
 			Repo:        req.Repo,
 			Commit:      req.Commit,
 			Channel:     req.Channel,
 			File:        req.File,
+			Line:        f.Line,
+			Column:      f.Column,
+			StartOffset: f.Start,
+			EndOffset:   f.End,
+			Snippet:     f.Snippet,
 			Content:     payload,
 			Fingerprint: fp,
 		}
@@ -262,21 +469,27 @@ func scanAndCreateIssues(c *fiber.Ctx, payload string, metadata string, req Scan
 			errs = append(errs, "db error")
 			continue
 		}
-		respIssues = append(respIssues, map[string]any{"id": issueID, "type": f.Type})
+		respIssues = append(respIssues, map[string]any{"id": issueID, "type": f.Type, "severity": f.Severity, "confidence": f.Confidence, "line": f.Line, "column": f.Column})
 		created++
 	}
 
-	return c.JSON(ScanResponse{
-		Success:    created > 0 && len(errs) == 0,
-		Created:    created,
-		Resolved:   resolvedCount,
-		Duplicates: duplicates,
-		Issues:     respIssues,
-		Errors:     errs,
-	})
+	return ScanResponse{
+		Success:     created > 0 && len(errs) == 0,
+		Created:     created,
+		Resolved:    resolvedCount,
+		Duplicates:  duplicates,
+		Allowlisted: report.Allowlisted,
+		Disabled:    report.Disabled,
+		Issues:      respIssues,
+		Errors:      errs,
+	}, nil
 }
 
-func autoResolveIssues(currentContent string, req ScanRequest) error {
+// autoResolveIssues resolves the active issues of req's repo/channel/file
+// context whose finding is not among currentFindings. currentFindings must
+// come from a scan that completed; a scan cut short by its deadline or
+// budget would resolve every issue.
+func autoResolveIssues(currentFindings []scanner.Finding, req ScanRequest) error {
 	// Get all active issues for this repo/channel/file context
 	var activeIssues []storage.Issue
 	query := storage.DB.Where("status = ?", "active")
@@ -307,7 +520,6 @@ func autoResolveIssues(currentContent string, req ScanRequest) error {
 
 	resolvedCount := 0
 	// making fingerprint
-	currentFindings := scanner.Scan(currentContent)
 	presentFingerprints := map[string]struct{}{}
 	for _, f := range currentFindings {
 		fp := fingerprint(req, f.Value, f.Type)
@@ -356,6 +568,187 @@ func fingerprint(req ScanRequest, value string, secretType string) string {
 	return hex.EncodeToString(sum)
 }
 
+// pathFilter builds the path filter for scans of repo: the global patterns
+// plus the repo's stored ones, whose excludes come last so they can
+// re-include with "!" and whose includes, if any, replace the global ones.
+func pathFilter(repo string) *scanner.PathFilter {
+	include, exclude := IncludePaths, ExcludePaths
+	if repo != "" {
+		var pf storage.PathFilter
+		if err := storage.DB.Where("repo = ?", repo).First(&pf).Error; err == nil {
+			if len(pf.Include) > 0 {
+				include = pf.Include
+			}
+			exclude = append(append([]string{}, exclude...), pf.Exclude...)
+		}
+	}
+	filter, err := scanner.NewPathFilter(include, exclude)
+	if err != nil {
+		// both sides are validated before they are stored
+		log.Printf("path filter for repo %q: %v", repo, err)
+		return nil
+	}
+	return filter
+}
+
+type PathFilterRequest struct {
+	Repo    string   `json:"repo"`
+	Include []string `json:"include"`
+	Exclude []string `json:"exclude"`
+}
+
+func listPathFiltersHandler(c *fiber.Ctx) error {
+	var filters []storage.PathFilter
+	if err := storage.DB.Order("repo").Find(&filters).Error; err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "db error"})
+	}
+	return c.JSON(fiber.Map{
+		"global": fiber.Map{"include": IncludePaths, "exclude": ExcludePaths},
+		"repos":  filters,
+	})
+}
+
+// putPathFilterHandler creates or replaces a repo's path filter.
+func putPathFilterHandler(c *fiber.Ctx) error {
+	var req PathFilterRequest
+	if err := c.BodyParser(&req); err != nil || req.Repo == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "invalid request"})
+	}
+	if _, err := scanner.NewPathFilter(req.Include, req.Exclude); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var pf storage.PathFilter
+	err := storage.DB.Where("repo = ?", req.Repo).First(&pf).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.Status(500).JSON(fiber.Map{"error": "db error"})
+	}
+	pf.Repo, pf.Include, pf.Exclude = req.Repo, req.Include, req.Exclude
+	if err := storage.DB.Save(&pf).Error; err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "db error"})
+	}
+	log.Printf("path filter saved: repo=%s include=%d exclude=%d", pf.Repo, len(pf.Include), len(pf.Exclude))
+	return c.JSON(pf)
+}
+
+func deletePathFilterHandler(c *fiber.Ctx) error {
+	id := c.Params("id")
+	res := storage.DB.Where("id = ?", id).Delete(&storage.PathFilter{})
+	if res.Error != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "db error"})
+	}
+	if res.RowsAffected == 0 {
+		return c.Status(404).JSON(fiber.Map{"error": "not found"})
+	}
+	return c.JSON(fiber.Map{"success": true, "id": id})
+}
+
+// rulesHandler lists the rule catalog. With a repo or channel query
+// parameter, enabled and severity reflect that scope's overrides.
+func rulesHandler(c *fiber.Ctx) error {
+	rules := scanner.Catalog()
+	overrides := ruleOverrides(c.Query("repo"), c.Query("channel"))
+	for i, r := range rules {
+		o, ok := overrides[r.ID]
+		if !ok {
+			continue
+		}
+		if o.Disabled {
+			rules[i].Enabled = false
+		}
+		if o.Severity != "" {
+			rules[i].Severity = o.Severity
+		}
+	}
+	return c.JSON(fiber.Map{
+		"version": scanner.RulePackVersion,
+		"rules":   rules,
+	})
+}
+
+// ruleOverrides loads the rule overrides for a scan of repo or channel,
+// keyed by rule id. A repo override wins over a channel override for the
+// same rule.
+func ruleOverrides(repo, channel string) map[string]scanner.Override {
+	rows, err := storage.RuleOverridesFor(repo, channel)
+	if err != nil {
+		log.Printf("rule overrides lookup failed: %v", err)
+		return nil
+	}
+	out := make(map[string]scanner.Override, len(rows))
+	for _, o := range rows {
+		out[o.RuleID] = scanner.Override{Disabled: o.Disabled, Severity: o.Severity}
+	}
+	return out
+}
+
+type RuleOverrideRequest struct {
+	RuleID   string `json:"ruleId"`
+	Repo     string `json:"repo"`
+	Channel  string `json:"channel"`
+	Disabled bool   `json:"disabled"`
+	Severity string `json:"severity"`
+}
+
+func listRuleOverridesHandler(c *fiber.Ctx) error {
+	q := storage.DB.Order("rule_id, repo, channel")
+	if repo := c.Query("repo"); repo != "" {
+		q = q.Where("repo = ?", repo)
+	}
+	if channel := c.Query("channel"); channel != "" {
+		q = q.Where("channel = ?", channel)
+	}
+	var overrides []storage.RuleOverride
+	if err := q.Find(&overrides).Error; err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "db error"})
+	}
+	return c.JSON(overrides)
+}
+
+// putRuleOverrideHandler creates or replaces the override of one rule for
+// one repo or channel.
+func putRuleOverrideHandler(c *fiber.Ctx) error {
+	var req RuleOverrideRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "invalid request"})
+	}
+	if (req.Repo == "") == (req.Channel == "") {
+		return c.Status(400).JSON(fiber.Map{"error": "set exactly one of repo or channel"})
+	}
+	sev, err := scanner.ValidateOverride(req.RuleID, req.Severity)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if !req.Disabled && sev == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "override must disable the rule or set a severity"})
+	}
+
+	var o storage.RuleOverride
+	err = storage.DB.Where("rule_id = ? AND repo = ? AND channel = ?", req.RuleID, req.Repo, req.Channel).First(&o).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.Status(500).JSON(fiber.Map{"error": "db error"})
+	}
+	o.RuleID, o.Repo, o.Channel = req.RuleID, req.Repo, req.Channel
+	o.Disabled, o.Severity = req.Disabled, sev
+	if err := storage.DB.Save(&o).Error; err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "db error"})
+	}
+	log.Printf("rule override saved: rule=%s repo=%s channel=%s disabled=%v severity=%s", o.RuleID, o.Repo, o.Channel, o.Disabled, o.Severity)
+	return c.JSON(o)
+}
+
+func deleteRuleOverrideHandler(c *fiber.Ctx) error {
+	id := c.Params("id")
+	res := storage.DB.Where("id = ?", id).Delete(&storage.RuleOverride{})
+	if res.Error != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "db error"})
+	}
+	if res.RowsAffected == 0 {
+		return c.Status(404).JSON(fiber.Map{"error": "not found"})
+	}
+	return c.JSON(fiber.Map{"success": true, "id": id})
+}
+
 func listTicketsHandler(c *fiber.Ctx) error {
 	var issues []storage.Issue
 	if err := storage.DB.Order("created_at desc").Find(&issues).Error; err != nil {
@@ -7,6 +7,8 @@ import (
 
 func SetupRoutes() *fiber.App {
 	app := fiber.New(fiber.Config{
+		// room for archive uploads to /scan/file
+		BodyLimit: 100 << 20,
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
 			code := fiber.StatusInternalServerError
 			if e, ok := err.(*fiber.Error); ok {
@@ -30,6 +32,14 @@ func SetupRoutes() *fiber.App {
 	app.Get("/ping", pingHandler)
 	app.Post("/scan", scanHandler)
 	app.Post("/scan/bulk", scanBulkHandler)
+	app.Post("/scan/file", scanFileHandler)
+	app.Get("/rules", rulesHandler)
+	app.Get("/rules/overrides", listRuleOverridesHandler)
+	app.Put("/rules/overrides", putRuleOverrideHandler)
+	app.Delete("/rules/overrides/:id", deleteRuleOverrideHandler)
+	app.Get("/path-filters", listPathFiltersHandler)
+	app.Put("/path-filters", putPathFilterHandler)
+	app.Delete("/path-filters/:id", deletePathFilterHandler)
 	app.Get("/tickets", listTicketsHandler)
 	app.Post("/resolve/:id", resolveHandler)
 	app.Post("/ignore/:id", ignoreHandler)
@@ -11,23 +11,15 @@ import (
 	"regexp"
 	"strings"
 	"time"
+
+	"github.com/DevloperAmanSingh/secret-scanning/internal/scanner"
 )
 
 var linearURL = "https://api.linear.app/graphql"
 var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F-]{36}$`)
 
-func severityForType(t string) string {
-	lt := strings.ToLower(t)
-	if strings.Contains(lt, "aws") || strings.Contains(lt, "stripe") || strings.Contains(lt, "jwt") {
-		return "high"
-	}
-	if strings.Contains(lt, "github") || strings.Contains(lt, "slack") || strings.Contains(lt, "google") {
-		return "medium"
-	}
-	return "low"
-}
-
-func CreateIssue(secretType, metadata string, ts time.Time) (string, error) {
+func CreateIssue(f scanner.Finding, metadata string, ts time.Time) (string, error) {
+	secretType := f.Type
 	token := os.Getenv("LINEAR_API_KEY")
 	teamID := os.Getenv("LINEAR_TEAM_ID")
 	if token == "" || teamID == "" {
@@ -37,12 +29,15 @@ func CreateIssue(secretType, metadata string, ts time.Time) (string, error) {
 		return "", fmt.Errorf("LINEAR_TEAM_ID must be a UUID")
 	}
 
-	// Build structured, redaction-safe description (no remediation)
-	desc := fmt.Sprintf("Severity: %s\nType: %s\nDetected: %s\n\nContext:\n%s\n\nNotes:\n- Values are not stored or logged.\n- API key: **** (redacted)",
-		severityForType(secretType),
-		secretType,
+	// Build structured, redaction-safe description
+	desc := fmt.Sprintf("Severity: %s\nConfidence: %.0f%%\nType: %s\nDetected: %s\n\nContext:\n%s\n\nLocation:\n%s\n\nRule:\n%s\n\nNotes:\n- Values are not stored or logged.\n- API key: **** (redacted)",
+		f.Severity,
+		f.Confidence*100,
+		typeDetail(f),
 		ts.Format(time.RFC3339),
 		metadata,
+		location(f),
+		ruleDoc(f.Type),
 	)
 
 	query := `
@@ -57,7 +52,7 @@ func CreateIssue(secretType, metadata string, ts time.Time) (string, error) {
 		"query": query,
 		"variables": map[string]interface{}{
 			"input": map[string]interface{}{
-				"title":       fmt.Sprintf("[%s] Secret detected: %s", severityForType(secretType), secretType),
+				"title":       fmt.Sprintf("[%s] Secret detected: %s", f.Severity, secretType),
 				"description": desc,
 				"teamId":      teamID,
 			},
@@ -114,6 +109,50 @@ func CreateIssue(secretType, metadata string, ts time.Time) (string, error) {
 	return issueID, nil
 }
 
+func typeDetail(f scanner.Finding) string {
+	if f.KeyType == "" {
+		return f.Type
+	}
+	if f.KeyBits > 0 {
+		return fmt.Sprintf("%s (%s, %d-bit)", f.Type, f.KeyType, f.KeyBits)
+	}
+	return fmt.Sprintf("%s (%s)", f.Type, f.KeyType)
+}
+
+// ruleDoc describes the rule behind a finding type and links to its
+// remediation docs.
+func ruleDoc(secretType string) string {
+	info, ok := scanner.LookupRule(secretType)
+	if !ok {
+		return secretType
+	}
+	doc := fmt.Sprintf("%s (%s)", info.Description, info.Provider)
+	if info.Remediation != "" {
+		doc += "\nRemediation: " + info.Remediation
+	}
+	return doc
+}
+
+func location(f scanner.Finding) string {
+	loc := fmt.Sprintf("Line %d, column %d (bytes %d-%d)", f.Line, f.Column, f.Start, f.End)
+	if f.Cell != nil {
+		loc = fmt.Sprintf("Notebook cell %d (%s), %s", *f.Cell, f.CellPart, strings.ToLower(loc[:1])+loc[1:])
+	}
+	if f.LogField != "" {
+		loc += "\nLog field: " + f.LogField
+	}
+	if f.KeyPath != "" {
+		loc += "\nKey: " + f.KeyPath
+	}
+	if len(f.Encoding) > 0 {
+		loc += "\nFound after decoding: " + strings.Join(f.Encoding, " → ")
+	}
+	if f.Snippet != "" {
+		loc += "\n```\n" + f.Snippet + "\n```"
+	}
+	return loc
+}
+
 func getCompletedStateID(token, teamID string) (string, error) {
 	query := `
 	query($teamId: ID!) {
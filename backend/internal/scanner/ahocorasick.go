@@ -0,0 +1,86 @@
+package scanner
+
+// keywordMatcher is an Aho-Corasick automaton over ASCII-case-folded bytes.
+// It finds which of a set of keywords occur in a text in a single pass, so
+// rules can be skipped without running their regex.
+type keywordMatcher struct {
+	// next is the full DFA transition table; fail links are already folded in.
+	next [][256]int32
+	// out lists the keyword ids recognised on entering each state.
+	out [][]int
+	n   int
+}
+
+func lowerASCII(b byte) byte {
+	if 'A' <= b && b <= 'Z' {
+		return b + 'a' - 'A'
+	}
+	return b
+}
+
+func newKeywordMatcher(keywords []string) *keywordMatcher {
+	m := &keywordMatcher{next: make([][256]int32, 1), out: make([][]int, 1), n: len(keywords)}
+	// trie; -1 marks a missing edge until the BFS below fills it in
+	for i := range m.next[0] {
+		m.next[0][i] = -1
+	}
+	for id, kw := range keywords {
+		s := int32(0)
+		for i := 0; i < len(kw); i++ {
+			c := lowerASCII(kw[i])
+			if m.next[s][c] < 0 {
+				m.next = append(m.next, [256]int32{})
+				m.out = append(m.out, nil)
+				t := int32(len(m.next) - 1)
+				for j := range m.next[t] {
+					m.next[t][j] = -1
+				}
+				m.next[s][c] = t
+			}
+			s = m.next[s][c]
+		}
+		m.out[s] = append(m.out[s], id)
+	}
+
+	fail := make([]int32, len(m.next))
+	queue := []int32{}
+	for c := 0; c < 256; c++ {
+		if t := m.next[0][c]; t > 0 {
+			queue = append(queue, t)
+		} else {
+			m.next[0][c] = 0
+		}
+	}
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+		m.out[s] = append(m.out[s], m.out[fail[s]]...)
+		for c := 0; c < 256; c++ {
+			t := m.next[s][c]
+			if t < 0 {
+				m.next[s][c] = m.next[fail[s]][c]
+				continue
+			}
+			fail[t] = m.next[fail[s]][c]
+			queue = append(queue, t)
+		}
+	}
+	return m
+}
+
+// match reports, per keyword id, whether the keyword occurs in text.
+func (m *keywordMatcher) match(text string) []bool {
+	hits := make([]bool, m.n)
+	remaining := m.n
+	s := int32(0)
+	for i := 0; i < len(text) && remaining > 0; i++ {
+		s = m.next[s][lowerASCII(text[i])]
+		for _, id := range m.out[s] {
+			if !hits[id] {
+				hits[id] = true
+				remaining--
+			}
+		}
+	}
+	return hits
+}
@@ -0,0 +1,218 @@
+package scanner
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"unicode/utf8"
+)
+
+// ArchiveLimits bound the work done unpacking an upload so a zip bomb or a
+// deeply nested archive can't exhaust memory or CPU.
+type ArchiveLimits struct {
+	MaxEntries   int   // files visited across all nesting levels
+	MaxEntrySize int64 // uncompressed bytes read from any single entry
+	MaxTotalSize int64 // uncompressed bytes read across all entries
+	MaxDepth     int   // archives inside archives
+}
+
+var DefaultArchiveLimits = ArchiveLimits{
+	MaxEntries:   10000,
+	MaxEntrySize: 10 << 20,
+	MaxTotalSize: 200 << 20,
+	MaxDepth:     3,
+}
+
+var ErrArchiveLimit = errors.New("archive limit exceeded")
+
+// ArchiveEntry is a regular file found while walking an upload. Path is the
+// full member path, e.g. "app.zip!/lib/inner.jar!/config.properties".
+type ArchiveEntry struct {
+	Path    string
+	Content []byte
+	// Skipped is set, with a reason, for entries that were not read
+	// because they look binary or are too large.
+	Skipped string
+}
+
+// IsArchive reports whether data is a zip (including jar/war), tar or gzip
+// file, judged by magic bytes first and the file name second. A gzip file
+// is walked as a tarball when it holds one and as a single member, such as
+// app.log.gz, when it doesn't.
+func IsArchive(name string, data []byte) bool {
+	return archiveKind(name, data) != ""
+}
+
+func archiveKind(name string, data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")), bytes.HasPrefix(data, []byte("PK\x05\x06")):
+		return "zip"
+	case bytes.HasPrefix(data, []byte{0x1f, 0x8b}):
+		return "gzip"
+	case isTar(data):
+		return "tar"
+	}
+	lower := strings.ToLower(name)
+	for _, ext := range []string{".zip", ".jar", ".war", ".ear"} {
+		if strings.HasSuffix(lower, ext) {
+			return "zip"
+		}
+	}
+	if strings.HasSuffix(lower, ".tar") {
+		return "tar"
+	}
+	if strings.HasSuffix(lower, ".gz") || strings.HasSuffix(lower, ".tgz") {
+		return "gzip"
+	}
+	return ""
+}
+
+func isTar(head []byte) bool {
+	return len(head) > 262 && string(head[257:262]) == "ustar"
+}
+
+// WalkArchive calls fn for every regular file in the archive data named
+// name, descending into nested archives up to limits.MaxDepth. Entries that
+// look binary or exceed MaxEntrySize are passed with Skipped set. Exceeding
+// MaxEntries or MaxTotalSize stops the walk with ErrArchiveLimit.
+func WalkArchive(name string, data []byte, limits ArchiveLimits, fn func(ArchiveEntry) error) error {
+	w := &archiveWalker{limits: limits, fn: fn}
+	return w.walk(name, data, 0)
+}
+
+type archiveWalker struct {
+	limits  ArchiveLimits
+	fn      func(ArchiveEntry) error
+	fnErr   error
+	entries int
+	total   int64
+}
+
+func (w *archiveWalker) emit(e ArchiveEntry) error {
+	w.fnErr = w.fn(e)
+	return w.fnErr
+}
+
+func (w *archiveWalker) walk(name string, data []byte, depth int) error {
+	switch archiveKind(name, data) {
+	case "zip":
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		for _, zf := range zr.File {
+			if zf.FileInfo().IsDir() {
+				continue
+			}
+			rc, err := zf.Open()
+			if err != nil {
+				return fmt.Errorf("%s!/%s: %w", name, zf.Name, err)
+			}
+			err = w.entry(name, zf.Name, rc, depth)
+			rc.Close()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	case "gzip":
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		defer gz.Close()
+		br := bufio.NewReader(gz)
+		if head, _ := br.Peek(263); isTar(head) {
+			return w.walkTar(name, br, depth)
+		}
+		return w.entry(name, gzipMember(name, gz.Name), br, depth)
+	case "tar":
+		return w.walkTar(name, bytes.NewReader(data), depth)
+	}
+	return fmt.Errorf("%s: not an archive", name)
+}
+
+// gzipMember names the single file in a gzip stream: the name stored in
+// its header, or the archive's own name without ".gz".
+func gzipMember(archive, stored string) string {
+	if stored != "" {
+		return stored
+	}
+	base := path.Base(strings.ReplaceAll(archive, "!/", "/"))
+	if trimmed := strings.TrimSuffix(base, path.Ext(base)); trimmed != "" && trimmed != base {
+		return trimmed
+	}
+	return base
+}
+
+func (w *archiveWalker) walkTar(name string, r io.Reader, depth int) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := w.entry(name, hdr.Name, tr, depth); err != nil {
+			return err
+		}
+	}
+}
+
+// entry reads one member, enforcing the limits on the bytes actually
+// decompressed rather than trusting the sizes in archive headers.
+func (w *archiveWalker) entry(archive, member string, r io.Reader, depth int) error {
+	w.entries++
+	if w.entries > w.limits.MaxEntries {
+		return fmt.Errorf("%w: more than %d entries", ErrArchiveLimit, w.limits.MaxEntries)
+	}
+	full := archive + "!/" + path.Clean(strings.TrimPrefix(member, "/"))
+	data, err := io.ReadAll(io.LimitReader(r, w.limits.MaxEntrySize+1))
+	if err != nil {
+		return fmt.Errorf("%s: %w", full, err)
+	}
+	w.total += int64(len(data))
+	if w.total > w.limits.MaxTotalSize {
+		return fmt.Errorf("%w: more than %d bytes uncompressed", ErrArchiveLimit, w.limits.MaxTotalSize)
+	}
+	if int64(len(data)) > w.limits.MaxEntrySize {
+		return w.emit(ArchiveEntry{Path: full, Skipped: "too large"})
+	}
+	if IsArchive(member, data) {
+		if depth+1 > w.limits.MaxDepth {
+			return w.emit(ArchiveEntry{Path: full, Skipped: "archive nested too deep"})
+		}
+		err := w.walk(full, data, depth+1)
+		if err == nil || errors.Is(err, ErrArchiveLimit) || w.fnErr != nil {
+			return err
+		}
+		// a corrupt or mislabelled member shouldn't fail the whole upload
+		return w.emit(ArchiveEntry{Path: full, Skipped: "unreadable archive"})
+	}
+	if IsBinary(data) {
+		return w.emit(ArchiveEntry{Path: full, Skipped: "binary"})
+	}
+	return w.emit(ArchiveEntry{Path: full, Content: data})
+}
+
+// IsBinary reports whether Sniff classifies data as binary.
+func IsBinary(data []byte) bool {
+	head := data
+	if len(head) > sniffSize+utf8.UTFMax {
+		head = head[:sniffSize+utf8.UTFMax]
+	}
+	kind, _ := Sniff(string(head))
+	return kind == ContentBinary
+}
@@ -0,0 +1,99 @@
+package scanner
+
+// secretsManagementGuide is the remediation link for secrets that aren't
+// tied to one provider.
+const secretsManagementGuide = "https://cheatsheetseries.owasp.org/cheatsheets/Secrets_Management_Cheat_Sheet.html"
+
+// Rule sources reported in the catalog.
+const (
+	SourceBuiltin = "built-in"
+	SourceCustom  = "custom"
+)
+
+// RuleInfo describes a rule or detector for people reading findings: the
+// dashboard lists them and tickets link to their remediation docs.
+type RuleInfo struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	Severity    string `json:"severity"`
+	// Confidence is left out for detectors that score each match.
+	Confidence  float64 `json:"confidence,omitempty"`
+	Provider    string  `json:"provider"`
+	Remediation string  `json:"remediation,omitempty"`
+	// Example is a value the rule reports, taken from its MustMatch list.
+	Example string `json:"example,omitempty"`
+	// Extensions lists the file extensions the rule is limited to.
+	Extensions []string `json:"extensions,omitempty"`
+	Enabled    bool     `json:"enabled"`
+	Source     string   `json:"source"`
+}
+
+// detectorInfo describes the finding types that don't come from a rule.
+var detectorInfo = []RuleInfo{
+	{ID: "SensitiveKeyValue", Description: "Value under a sensitive key name in a JSON, YAML, TOML, dotenv or properties file", Confidence: 0.5, Example: `{"db": {"password": "Vq3rT8mZ2pLx"}}`},
+	{ID: "HighEntropyBase64", Description: "High-entropy base64 string assigned to a secret-sounding name", Example: "SESSION_SECRET=q8Vx2LmR7tKp0YwZ4bNc9HsJ3fDg6AeU1iOrQyTz5k=="},
+	{ID: "HighEntropyHex", Description: "High-entropy hex string assigned to a secret-sounding name", Example: "webhook_secret: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b"},
+}
+
+// Catalog lists every rule of the active rule set, disabled ones included,
+// followed by the built-in detectors.
+func Catalog() []RuleInfo {
+	rs := active
+	out := make([]RuleInfo, 0, len(rs.all)+len(detectorInfo))
+	for _, r := range rs.all {
+		out = append(out, r.info(!rs.disabled[r.ID]))
+	}
+	for _, d := range detectorInfo {
+		out = append(out, d.detector())
+	}
+	return out
+}
+
+// LookupRule returns the catalog entry for a finding type. It is called per
+// finding, so it goes through the rule set's index rather than Catalog.
+func LookupRule(id string) (RuleInfo, bool) {
+	rs := active
+	if r, ok := rs.byID[id]; ok {
+		return r.info(!rs.disabled[r.ID]), true
+	}
+	for _, d := range detectorInfo {
+		if d.ID == id {
+			return d.detector(), true
+		}
+	}
+	return RuleInfo{}, false
+}
+
+// detector fills in the fields shared by every built-in detector.
+func (d RuleInfo) detector() RuleInfo {
+	d.Severity = detectorSeverities[d.ID]
+	d.Provider = "Generic"
+	d.Remediation = secretsManagementGuide
+	d.Enabled = true
+	d.Source = SourceBuiltin
+	return d
+}
+
+func (r *Rule) info(enabled bool) RuleInfo {
+	info := RuleInfo{
+		ID:          r.ID,
+		Description: r.Description,
+		Severity:    r.Severity,
+		Confidence:  r.confidence(),
+		Provider:    r.Provider,
+		Remediation: r.Remediation,
+		Extensions:  r.Extensions,
+		Enabled:     enabled,
+		Source:      SourceBuiltin,
+	}
+	if info.Provider == "" {
+		info.Provider = "Generic"
+	}
+	if len(r.MustMatch) > 0 {
+		info.Example = r.MustMatch[0]
+	}
+	if r.Custom {
+		info.Source = SourceCustom
+	}
+	return info
+}
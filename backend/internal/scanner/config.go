@@ -0,0 +1,176 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// RuleConfig is the on-disk shape of a rule in a rules file.
+type RuleConfig struct {
+	ID          string `toml:"id" yaml:"id"`
+	Description string `toml:"description" yaml:"description"`
+	Regex       string `toml:"regex" yaml:"regex"`
+	Severity    string `toml:"severity" yaml:"severity"`
+	// Confidence is 0..1; zero uses DefaultConfidence.
+	Confidence float64  `toml:"confidence" yaml:"confidence"`
+	Keywords   []string `toml:"keywords" yaml:"keywords"`
+	Allowlist  []string `toml:"allowlist" yaml:"allowlist"`
+	// DisabledFilters turns off built-in false-positive filters by name:
+	// example, repeated, low-entropy, template.
+	DisabledFilters []string `toml:"disabledFilters" yaml:"disabledFilters"`
+	// MustMatch and MustNotMatch are examples checked when the file is
+	// loaded: each MustMatch example has to produce a finding and no
+	// MustNotMatch example may.
+	MustMatch    []string `toml:"mustMatch" yaml:"mustMatch"`
+	MustNotMatch []string `toml:"mustNotMatch" yaml:"mustNotMatch"`
+	// Priority breaks ties with overlapping rules; higher wins, built-in
+	// provider rules are 0.
+	Priority int `toml:"priority" yaml:"priority"`
+	// Extensions limits the rule to files ending in one of them, e.g.
+	// [".tf", ".tfvars"].
+	Extensions []string `toml:"extensions" yaml:"extensions"`
+}
+
+type RulesFile struct {
+	Rules []RuleConfig `toml:"rules" yaml:"rules"`
+}
+
+var severities = map[string]bool{"critical": true, "high": true, "medium": true, "low": true}
+
+// ReadRulesFile parses a TOML or YAML rules file (picked by extension) and
+// validates every rule. Pass the result to SetRules to activate it.
+func ReadRulesFile(path string) ([]*Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	custom, err := parseRulesFile(data, filepath.Ext(path))
+	if err != nil {
+		return nil, fmt.Errorf("rules file %s: %w", path, err)
+	}
+	return custom, nil
+}
+
+// parseRulesFile decodes and compiles rules file data in the format named
+// by ext.
+func parseRulesFile(data []byte, ext string) ([]*Rule, error) {
+	var rf RulesFile
+	var err error
+	switch strings.ToLower(ext) {
+	case ".toml":
+		err = toml.Unmarshal(data, &rf)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &rf)
+	default:
+		return nil, fmt.Errorf("unsupported extension, want .toml, .yaml or .yml")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return CompileRules(rf.Rules)
+}
+
+// CompileRules validates rule configs and compiles them into rules. All
+// problems are reported together rather than stopping at the first one.
+func CompileRules(cfgs []RuleConfig) ([]*Rule, error) {
+	var errs []error
+	seen := map[string]bool{}
+	out := make([]*Rule, 0, len(cfgs))
+	for i, c := range cfgs {
+		name := fmt.Sprintf("rule %d", i+1)
+		if c.ID != "" {
+			name = fmt.Sprintf("rule %d (%s)", i+1, c.ID)
+		}
+		r, err := compileRule(c)
+		if err == nil && seen[c.ID] {
+			err = fmt.Errorf("duplicate id")
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		seen[c.ID] = true
+		out = append(out, r)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return out, nil
+}
+
+func compileRule(c RuleConfig) (*Rule, error) {
+	if c.ID == "" {
+		return nil, fmt.Errorf("missing id")
+	}
+	if c.Regex == "" {
+		return nil, fmt.Errorf("missing regex")
+	}
+	re, err := regexp.Compile(c.Regex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex: %w", err)
+	}
+	if err := LintRegex(re); err != nil {
+		return nil, fmt.Errorf("regex rejected: %w", err)
+	}
+	sev := strings.ToLower(c.Severity)
+	if sev == "" {
+		sev = "medium"
+	}
+	if !severities[sev] {
+		return nil, fmt.Errorf("invalid severity %q, want critical, high, medium or low", c.Severity)
+	}
+	if c.Confidence < 0 || c.Confidence > 1 {
+		return nil, fmt.Errorf("invalid confidence %v, want 0..1", c.Confidence)
+	}
+	for _, name := range c.DisabledFilters {
+		if !filterNames[name] {
+			return nil, fmt.Errorf("unknown filter %q in disabledFilters, want example, repeated, low-entropy or template", name)
+		}
+	}
+	r := &Rule{ID: c.ID, Description: c.Description, Severity: sev, Confidence: c.Confidence, Regex: re, Keywords: c.Keywords, DisabledFilters: c.DisabledFilters, Priority: c.Priority, Custom: true}
+	if len(c.Allowlist) > 0 {
+		res, err := compileAll(c.Allowlist)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowlist regex: %w", err)
+		}
+		r.Allowlists = []Allowlist{{Regexes: res}}
+	}
+	r.MustMatch, r.MustNotMatch = c.MustMatch, c.MustNotMatch
+	for _, ex := range c.MustMatch {
+		if found, _ := matchRule(r, "", ex); len(found) == 0 {
+			return nil, fmt.Errorf("mustMatch example %q is not matched", ex)
+		}
+	}
+	for _, ex := range c.MustNotMatch {
+		if found, _ := matchRule(r, "", ex); len(found) > 0 {
+			return nil, fmt.Errorf("mustNotMatch example %q is matched", ex)
+		}
+	}
+	// set after the examples are checked, which are scanned without a path
+	for _, ext := range c.Extensions {
+		if !strings.HasPrefix(ext, ".") || len(ext) < 2 {
+			return nil, fmt.Errorf("invalid extension %q, want e.g. \".tfvars\"", ext)
+		}
+	}
+	r.Extensions = c.Extensions
+	return r, nil
+}
+
+func compileAll(patterns []string) ([]*regexp.Regexp, error) {
+	out := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, re)
+	}
+	return out, nil
+}
@@ -0,0 +1,111 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCompileRules(t *testing.T) {
+	valid := RuleConfig{ID: "InternalToken", Regex: `itk_[a-z0-9]{32}`, MustMatch: []string{"itk_9f2kq8z3lp0vn7rt2yb5wm1xc4hd6gja"}}
+	cases := []struct {
+		name string
+		cfgs []RuleConfig
+		err  string
+	}{
+		{"valid", []RuleConfig{valid}, ""},
+		{"duplicate id", []RuleConfig{valid, valid}, "rule 2 (InternalToken): duplicate id"},
+		{"missing id", []RuleConfig{{Regex: `x`}}, "rule 1: missing id"},
+		{"missing regex", []RuleConfig{{ID: "x"}}, "missing regex"},
+		{"bad regex", []RuleConfig{{ID: "x", Regex: `itk_[a-z`}}, "invalid regex"},
+		{"linted regex", []RuleConfig{{ID: "x", Regex: `(a+)+`}}, "regex rejected: nested quantifier"},
+		{"unknown severity", []RuleConfig{{ID: "x", Regex: `x`, Severity: "urgent"}}, `invalid severity "urgent"`},
+		{"confidence out of range", []RuleConfig{{ID: "x", Regex: `x`, Confidence: 1.5}}, "invalid confidence"},
+		{"unknown filter", []RuleConfig{{ID: "x", Regex: `x`, DisabledFilters: []string{"entropy"}}}, `unknown filter "entropy"`},
+		{"bad allowlist", []RuleConfig{{ID: "x", Regex: `x`, Allowlist: []string{`(`}}}, "invalid allowlist regex"},
+		{"mustMatch fails", []RuleConfig{{ID: "x", Regex: `itk_[a-z0-9]{32}`, MustMatch: []string{"itk_short"}}}, `mustMatch example "itk_short" is not matched`},
+		{"mustNotMatch fails", []RuleConfig{{ID: "x", Regex: `itk_[a-z0-9]{32}`, MustNotMatch: []string{"itk_9f2kq8z3lp0vn7rt2yb5wm1xc4hd6gja"}}}, "mustNotMatch example"},
+		{"allowlisted mustNotMatch", []RuleConfig{{ID: "x", Regex: `itk_[a-z0-9]{32}`, Allowlist: []string{`^itk_test`}, MustNotMatch: []string{"itk_test8z3lp0vn7rt2yb5wm1xc4hd6gja"}}}, ""},
+		{"extension without a dot", []RuleConfig{{ID: "x", Regex: `x`, Extensions: []string{"tfvars"}}}, `invalid extension "tfvars"`},
+		{"all errors reported", []RuleConfig{{ID: "a", Regex: `(`}, {ID: "b", Regex: `x`, Severity: "urgent"}}, "rule 2 (b): invalid severity"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rules, err := CompileRules(tc.cfgs)
+			if tc.err == "" {
+				if err != nil || len(rules) != len(tc.cfgs) {
+					t.Fatalf("got %d rules, %v", len(rules), err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("got %v, want an error containing %q", err, tc.err)
+			}
+			if rules != nil {
+				t.Errorf("rules returned alongside an error: %v", rules)
+			}
+		})
+	}
+}
+
+func TestCompileRulesDefaults(t *testing.T) {
+	rules, err := CompileRules([]RuleConfig{{ID: "x", Regex: `x`, Severity: "HIGH"}, {ID: "y", Regex: `y`}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rules[0].Severity != "high" || rules[1].Severity != "medium" {
+		t.Errorf("severities = %s, %s, want high, medium", rules[0].Severity, rules[1].Severity)
+	}
+	if !rules[0].Custom || rules[0].confidence() != DefaultConfidence {
+		t.Errorf("got %+v, want a custom rule with the default confidence", rules[0])
+	}
+}
+
+func TestRulesFileExtensions(t *testing.T) {
+	const token = "itk_9f2kq8z3lp0vn7rt2yb5wm1xc4hd6gja"
+	files := map[string]string{
+		"rules.toml": `
+[[rules]]
+id = "InternalToken"
+regex = '''itk_[a-z0-9]{32}'''
+extensions = [".tf", ".TFVARS"]
+mustMatch = ["` + token + `"]
+`,
+		"rules.yaml": `
+rules:
+  - id: InternalToken
+    regex: 'itk_[a-z0-9]{32}'
+    extensions: [".tf", ".TFVARS"]
+    mustMatch: ["` + token + `"]
+`,
+	}
+	for name, data := range files {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			rules, err := ReadRulesFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// mustMatch examples are checked without a path, before the
+			// extension filter applies
+			r := rules[0]
+			for path, want := range map[string]bool{"main.tf": true, "prod.tfvars": true, "modules/db/prod.TFVars": true, "main.go": false, "": false} {
+				if found, _ := matchRule(r, path, "token = "+token); (len(found) == 1) != want {
+					t.Errorf("%q: reported = %v, want %v", path, len(found) == 1, want)
+				}
+			}
+		})
+	}
+
+	path := filepath.Join(t.TempDir(), "rules.json")
+	if err := os.WriteFile(path, []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadRulesFile(path); err == nil || !strings.Contains(err.Error(), "unsupported extension") {
+		t.Errorf("got %v, want an unsupported extension error", err)
+	}
+}
@@ -0,0 +1,88 @@
+package scanner
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// uriRule builds a rule matching scheme://userinfo@host URIs. Its validator
+// narrows the finding to the credential inside the userinfo, so the value
+// (and so the fingerprint) doesn't change when only the host does.
+func uriRule(id, description, severity, provider string, schemes []string, tokenOnly bool) *Rule {
+	return &Rule{
+		ID:          id,
+		Description: description,
+		Severity:    severity,
+		Confidence:  0.8,
+		Provider:    provider,
+		Remediation: secretsManagementGuide,
+		Keywords:    schemes,
+		Regex:       regexp.MustCompile(`(?i)\b(?:` + strings.Join(quoteAll(schemes), "|") + `)://[^\s/@'"<>\x60]+@[^\s'"<>\x60]+`),
+		Validate: func(match string, f *Finding) bool {
+			return narrowToCredential(match, f, tokenOnly)
+		},
+	}
+}
+
+func quoteAll(ss []string) []string {
+	out := make([]string, len(ss))
+	for i, s := range ss {
+		out[i] = regexp.QuoteMeta(s)
+	}
+	return out
+}
+
+// narrowToCredential points f at the password in a URI's userinfo, or at the
+// username when it is the only part and allowUserToken is set (the
+// https://TOKEN@host form). Placeholder credentials are dropped.
+func narrowToCredential(match string, f *Finding, allowUserToken bool) bool {
+	sep := strings.Index(match, "://")
+	if sep < 0 {
+		return false
+	}
+	// the regex keeps '@' and '/' out of the userinfo, so the first '@'
+	// ends it; later ones belong to the path or query
+	at := strings.IndexByte(match[sep+3:], '@')
+	if at < 0 {
+		return false
+	}
+	userinfo := match[sep+3 : sep+3+at]
+	user, pass, hasPass := strings.Cut(userinfo, ":")
+	credStart := sep + 3 + len(user) + 1
+	cred := pass
+	if !hasPass || pass == "" {
+		if !allowUserToken || len(user) < 20 || !hasLetterAndDigit(user) {
+			return false
+		}
+		credStart, cred, user = sep+3, user, ""
+	}
+	if isPlaceholderCredential(user, cred) {
+		return false
+	}
+	f.Value = cred
+	f.Start += credStart
+	f.End = f.Start + len(cred)
+	return true
+}
+
+var placeholderPasswords = map[string]bool{
+	"password": true, "passwd": true, "pass": true, "pwd": true, "secret": true,
+	"changeme": true, "example": true, "test": true, "user": true, "username": true,
+	"root": true, "admin": true, "guest": true, "token": true, "mypassword": true,
+	"yourpassword": true, "your_password": true, "your-password": true, "dbpass": true,
+}
+
+var placeholderPattern = regexp.MustCompile(`^(?:\$\{.*\}|\{\{.*\}\}|<.*>|\[.*\]|%s|%\(.*\)s|\$[A-Za-z_][A-Za-z0-9_]*|:[a-z_]+|x+|\*+|\.+)$`)
+
+// isPlaceholderCredential reports docs-style credentials such as
+// user:password, ${DB_PASSWORD} or <password>, and user==password dev
+// defaults like postgres:postgres.
+func isPlaceholderCredential(user, cred string) bool {
+	decoded, err := url.PathUnescape(cred)
+	if err != nil {
+		decoded = cred
+	}
+	lower := strings.ToLower(decoded)
+	return placeholderPasswords[lower] || placeholderPattern.MatchString(lower) || (user != "" && strings.EqualFold(user, decoded))
+}
@@ -0,0 +1,107 @@
+package scanner
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DecodeDepth is how many layers of base64, URL or hex encoding Scan peels
+// off looking for secrets (base64 inside base64 is depth 2). 0 disables
+// decoding.
+var DecodeDepth = 2
+
+const minDecodedLength = 8
+
+var (
+	base64Segment = regexp.MustCompile(`[A-Za-z0-9+/_-]{16,}={0,2}`)
+	hexSegment    = regexp.MustCompile(`\b(?:[0-9a-fA-F]{2}){16,}\b`)
+	urlSegment    = regexp.MustCompile(`(?:[A-Za-z0-9._~+!*()-]|%[0-9A-Fa-f]{2})*%[0-9A-Fa-f]{2}(?:[A-Za-z0-9._~+!*()-]|%[0-9A-Fa-f]{2})*`)
+)
+
+// encodedSegment is a span of content that decodes to printable text.
+type encodedSegment struct {
+	start, end int
+	encoding   string
+	decoded    string
+}
+
+func encodedSegments(content string) []encodedSegment {
+	segs := []encodedSegment{}
+	for _, loc := range base64Segment.FindAllStringIndex(content, -1) {
+		if d, ok := decodeBase64(content[loc[0]:loc[1]]); ok {
+			segs = append(segs, encodedSegment{loc[0], loc[1], "base64", d})
+		}
+	}
+	for _, loc := range hexSegment.FindAllStringIndex(content, -1) {
+		if b, err := hex.DecodeString(content[loc[0]:loc[1]]); err == nil && printable(string(b)) {
+			segs = append(segs, encodedSegment{loc[0], loc[1], "hex", string(b)})
+		}
+	}
+	if strings.IndexByte(content, '%') >= 0 {
+		for _, loc := range urlSegment.FindAllStringIndex(content, -1) {
+			if d, err := url.QueryUnescape(content[loc[0]:loc[1]]); err == nil && printable(d) {
+				segs = append(segs, encodedSegment{loc[0], loc[1], "url", d})
+			}
+		}
+	}
+	return segs
+}
+
+func decodeBase64(s string) (string, bool) {
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+		if b, err := enc.DecodeString(s); err == nil {
+			return string(b), printable(string(b))
+		}
+	}
+	return "", false
+}
+
+// printable reports whether s looks like text rather than binary: valid
+// UTF-8 with at most 5% non-printable runes.
+func printable(s string) bool {
+	if len(s) < minDecodedLength || !utf8.ValidString(s) {
+		return false
+	}
+	bad, n := 0, 0
+	for _, r := range s {
+		n++
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			bad++
+		}
+	}
+	return bad*20 <= n
+}
+
+// scanDecoded scans the decoded form of every encoded segment in content,
+// recursing up to depth layers. Findings keep the decoded secret as Value
+// but point Start/End at the outermost encoded segment, with the encodings
+// peeled to reach them listed outermost first.
+func scanDecoded(ctx context.Context, path string, content string, depth int) ([]Finding, []span, error) {
+	if depth <= 0 {
+		return nil, nil, nil
+	}
+	findings := []Finding{}
+	revealing := []span{}
+	for _, seg := range encodedSegments(content) {
+		inner, err := scanLayer(ctx, path, seg.decoded, depth-1, false)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, f := range inner {
+			f.decodedSpans = append([]span{{f.Start, f.End}}, f.decodedSpans...)
+			f.Start, f.End = seg.start, seg.end
+			f.Encoding = append([]string{seg.encoding}, f.Encoding...)
+			findings = append(findings, f)
+		}
+		if len(inner) > 0 {
+			revealing = append(revealing, span{seg.start, seg.end})
+		}
+	}
+	return findings, revealing, nil
+}
@@ -0,0 +1,161 @@
+package scanner
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Entropy thresholds (bits per character) above which a candidate token is
+// reported. Random base64 sits around 5.0-5.5 and random hex around 3.7-4.0
+// for 40 character strings, so these leave room for shorter tokens.
+var (
+	Base64EntropyThreshold = 4.5
+	HexEntropyThreshold    = 3.0
+)
+
+const (
+	entropyMinLength = 20
+	base64Charset    = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/=_-"
+	hexCharset       = "0123456789abcdefABCDEF"
+)
+
+var (
+	base64Candidate = regexp.MustCompile(`[A-Za-z0-9+/_-]{20,}={0,2}`)
+	hexCandidate    = regexp.MustCompile(`\b[0-9a-fA-F]{32,}\b`)
+	// secretAssignment matches the text just before a value assigned to a
+	// secret-sounding name: `API_SECRET=`, `"token": "`, `password := '`.
+	secretAssignment = regexp.MustCompile(`(?i)([a-z0-9_.-]*(?:secret|token|passw(?:or)?d|pwd|key|auth|credential|signature|salt)[a-z0-9_.-]*)["']?\s*(?:=|:=|:|=>)\s*["'\x60]?$`)
+	// authScheme matches an HTTP auth scheme just before a credential:
+	// `Authorization: Bearer `, `"Basic `.
+	authScheme = regexp.MustCompile(`(?i)\b(?:bearer|basic|token)\s+$`)
+	// publicName marks names of values that are meant to be shared, and of
+	// key ids such as a service account's private_key_id, which name a key
+	// without granting anything.
+	publicName = regexp.MustCompile(`(?i)public|publishable|pub_?key|checksum|digest|hash|integrity|commit|sha|key_?id$`)
+)
+
+// entropyContextWindow is how far before a token, on the same line, the
+// detector looks for the name it is assigned to.
+const entropyContextWindow = 80
+
+// assignedSecret reports whether the token at start is the value assigned
+// to a secret-sounding name or follows an auth scheme such as "Bearer".
+// Random-looking strings are everywhere - commit SHAs, go.sum h1: hashes,
+// SRI sha512- integrity strings, image digests - so entropy alone isn't
+// reported.
+func assignedSecret(content string, start int) bool {
+	from := start - entropyContextWindow
+	if from < 0 {
+		from = 0
+	}
+	before := content[from:start]
+	if nl := strings.LastIndexByte(before, '\n'); nl >= 0 {
+		before = before[nl+1:]
+	}
+	if authScheme.MatchString(before) {
+		return true
+	}
+	m := secretAssignment.FindStringSubmatch(before)
+	return m != nil && !publicName.MatchString(m[1])
+}
+
+// shannonEntropy returns the Shannon entropy of s in bits per rune.
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	counts := map[rune]int{}
+	for _, r := range s {
+		counts[r]++
+	}
+	n := float64(utf8.RuneCountInString(s))
+	e := 0.0
+	for _, c := range counts {
+		p := float64(c) / n
+		e -= p * math.Log2(p)
+	}
+	return e
+}
+
+// entropyConfidence maps an entropy value onto 0..1 between the threshold
+// and the maximum possible entropy for the charset.
+func entropyConfidence(entropy, threshold float64, charset string) float64 {
+	max := math.Log2(float64(len(charset)))
+	if max <= threshold {
+		return 1
+	}
+	c := (entropy - threshold) / (max - threshold)
+	// anything that clears the threshold is at least a weak signal
+	c = 0.5 + c/2
+	if c > 1 {
+		c = 1
+	}
+	return math.Round(c*100) / 100
+}
+
+func hasLetterAndDigit(s string) bool {
+	return strings.ContainsAny(s, "0123456789") && strings.IndexFunc(s, func(r rune) bool {
+		return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+	}) >= 0
+}
+
+type span struct{ start, end int }
+
+func overlapsAny(spans []span, start, end int) bool {
+	for _, s := range spans {
+		if start < s.end && s.start < end {
+			return true
+		}
+	}
+	return false
+}
+
+// scanEntropy reports base64 and hex tokens whose entropy clears the
+// configured thresholds and that are assigned to a secret-sounding name. It
+// runs alongside the regex rules, so a token a rule also matched is dropped
+// later by the overlap pass, where entropy findings have the lowest
+// priority. A hex token is not reported again as base64.
+func scanEntropy(content string) []Finding {
+	findings := []Finding{}
+	var claimed []span
+	for _, loc := range hexCandidate.FindAllStringIndex(content, -1) {
+		tok := content[loc[0]:loc[1]]
+		if !hasLetterAndDigit(tok) || !assignedSecret(content, loc[0]) {
+			continue
+		}
+		e := shannonEntropy(tok)
+		if e < HexEntropyThreshold || placeholderReason(tok, nil) != "" {
+			continue
+		}
+		findings = append(findings, Finding{
+			Type:       "HighEntropyHex",
+			Value:      tok,
+			Severity:   detectorSeverities["HighEntropyHex"],
+			Confidence: entropyConfidence(e, HexEntropyThreshold, hexCharset),
+			Start:      loc[0],
+			End:        loc[1],
+		})
+		claimed = append(claimed, span{loc[0], loc[1]})
+	}
+	for _, loc := range base64Candidate.FindAllStringIndex(content, -1) {
+		tok := content[loc[0]:loc[1]]
+		if len(tok) < entropyMinLength || overlapsAny(claimed, loc[0], loc[1]) || !hasLetterAndDigit(tok) || !assignedSecret(content, loc[0]) {
+			continue
+		}
+		e := shannonEntropy(tok)
+		if e < Base64EntropyThreshold || placeholderReason(tok, nil) != "" {
+			continue
+		}
+		findings = append(findings, Finding{
+			Type:       "HighEntropyBase64",
+			Value:      tok,
+			Severity:   detectorSeverities["HighEntropyBase64"],
+			Confidence: entropyConfidence(e, Base64EntropyThreshold, base64Charset),
+			Start:      loc[0],
+			End:        loc[1],
+		})
+	}
+	return findings
+}
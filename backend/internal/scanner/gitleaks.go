@@ -0,0 +1,204 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// gitleaksConfig mirrors the parts of a gitleaks v8 .gitleaks.toml that map
+// onto our rule model.
+type gitleaksConfig struct {
+	Title  string `toml:"title"`
+	Extend struct {
+		Path       string `toml:"path"`
+		UseDefault bool   `toml:"useDefault"`
+	} `toml:"extend"`
+	Rules []gitleaksRule `toml:"rules"`
+	// Allowlist is the legacy single global allowlist; Allowlists is the
+	// newer [[allowlists]] form. Both apply to every rule.
+	Allowlist  *gitleaksAllowlist  `toml:"allowlist"`
+	Allowlists []gitleaksAllowlist `toml:"allowlists"`
+}
+
+type gitleaksRule struct {
+	ID          string              `toml:"id"`
+	Description string              `toml:"description"`
+	Regex       string              `toml:"regex"`
+	SecretGroup int                 `toml:"secretGroup"`
+	Entropy     float64             `toml:"entropy"`
+	Keywords    []string            `toml:"keywords"`
+	Path        string              `toml:"path"`
+	Tags        []string            `toml:"tags"`
+	Allowlist   *gitleaksAllowlist  `toml:"allowlist"`
+	Allowlists  []gitleaksAllowlist `toml:"allowlists"`
+}
+
+type gitleaksAllowlist struct {
+	Description string   `toml:"description"`
+	Condition   string   `toml:"condition"`
+	Regexes     []string `toml:"regexes"`
+	RegexTarget string   `toml:"regexTarget"`
+	Paths       []string `toml:"paths"`
+	Stopwords   []string `toml:"stopwords"`
+	Commits     []string `toml:"commits"`
+}
+
+// maxExtendDepth bounds [extend] path chains so a config can't loop forever.
+const maxExtendDepth = 3
+
+// ReadGitleaksConfig parses a gitleaks .gitleaks.toml into rules. Rules
+// imported this way keep the gitleaks rule id and get "medium" severity since
+// gitleaks has no severity field. Path-only rules and commit allowlists have
+// no equivalent here and are skipped with a log line.
+func ReadGitleaksConfig(path string) ([]*Rule, error) {
+	rules, err := readGitleaksConfig(path, 0)
+	if err != nil {
+		return nil, fmt.Errorf("gitleaks config %s: %w", path, err)
+	}
+	return rules, nil
+}
+
+func readGitleaksConfig(path string, depth int) ([]*Rule, error) {
+	var cfg gitleaksConfig
+	if _, err := toml.DecodeFile(path, &cfg); err != nil {
+		return nil, err
+	}
+	if cfg.Extend.UseDefault {
+		log.Printf("scanner: gitleaks config %s: extend.useDefault ignored, built-in rules always apply", path)
+	}
+
+	var base []*Rule
+	if cfg.Extend.Path != "" {
+		if depth >= maxExtendDepth {
+			return nil, fmt.Errorf("extend chain deeper than %d", maxExtendDepth)
+		}
+		p := cfg.Extend.Path
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(filepath.Dir(path), p)
+		}
+		var err error
+		if base, err = readGitleaksConfig(p, depth+1); err != nil {
+			return nil, fmt.Errorf("extend %s: %w", cfg.Extend.Path, err)
+		}
+	}
+
+	global := []gitleaksAllowlist{}
+	if cfg.Allowlist != nil {
+		global = append(global, *cfg.Allowlist)
+	}
+	global = append(global, cfg.Allowlists...)
+	globalAllow, err := convertAllowlists(global)
+	if err != nil {
+		return nil, fmt.Errorf("global allowlist: %w", err)
+	}
+
+	var errs []error
+	byID := map[string]int{}
+	out := []*Rule{}
+	for _, r := range base {
+		byID[r.ID] = len(out)
+		out = append(out, r)
+	}
+	seen := map[string]bool{}
+	for i, gr := range cfg.Rules {
+		name := fmt.Sprintf("rule %d", i+1)
+		if gr.ID != "" {
+			name = fmt.Sprintf("rule %d (%s)", i+1, gr.ID)
+		}
+		if gr.Regex == "" && gr.Path != "" {
+			log.Printf("scanner: gitleaks config %s: %s is path-only, skipped", path, name)
+			continue
+		}
+		r, err := convertGitleaksRule(gr)
+		if err == nil && seen[gr.ID] {
+			err = fmt.Errorf("duplicate id")
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		seen[gr.ID] = true
+		if j, ok := byID[r.ID]; ok {
+			out[j] = r
+			continue
+		}
+		byID[r.ID] = len(out)
+		out = append(out, r)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	// the global allowlist also covers rules inherited through [extend]
+	for _, r := range out {
+		r.Allowlists = append(r.Allowlists, globalAllow...)
+	}
+	return out, nil
+}
+
+func convertGitleaksRule(gr gitleaksRule) (*Rule, error) {
+	r, err := compileRule(RuleConfig{ID: gr.ID, Description: gr.Description, Regex: gr.Regex, Keywords: gr.Keywords})
+	if err != nil {
+		return nil, err
+	}
+	if gr.SecretGroup < 0 || gr.SecretGroup > r.Regex.NumSubexp() {
+		return nil, fmt.Errorf("secretGroup %d out of range, regex has %d groups", gr.SecretGroup, r.Regex.NumSubexp())
+	}
+	r.SecretGroup = gr.SecretGroup
+	r.FirstGroup = gr.SecretGroup == 0
+	r.Entropy = gr.Entropy
+	r.Tags = gr.Tags
+	if gr.Path != "" {
+		if r.Path, err = regexp.Compile(gr.Path); err != nil {
+			return nil, fmt.Errorf("invalid path regex: %w", err)
+		}
+	}
+	lists := []gitleaksAllowlist{}
+	if gr.Allowlist != nil {
+		lists = append(lists, *gr.Allowlist)
+	}
+	lists = append(lists, gr.Allowlists...)
+	if r.Allowlists, err = convertAllowlists(lists); err != nil {
+		return nil, fmt.Errorf("allowlist: %w", err)
+	}
+	return r, nil
+}
+
+func convertAllowlists(lists []gitleaksAllowlist) ([]Allowlist, error) {
+	out := make([]Allowlist, 0, len(lists))
+	for _, gl := range lists {
+		if len(gl.Commits) > 0 {
+			log.Printf("scanner: gitleaks allowlist %q: commit allowlists are not supported, ignored", gl.Description)
+		}
+		switch strings.ToLower(gl.RegexTarget) {
+		case "", "secret", "match", "line":
+		default:
+			return nil, fmt.Errorf("invalid regexTarget %q", gl.RegexTarget)
+		}
+		switch strings.ToUpper(gl.Condition) {
+		case "", "OR", "AND":
+		default:
+			return nil, fmt.Errorf("invalid condition %q", gl.Condition)
+		}
+		a := Allowlist{
+			Description: gl.Description,
+			Condition:   strings.ToUpper(gl.Condition),
+			RegexTarget: strings.ToLower(gl.RegexTarget),
+			Stopwords:   gl.Stopwords,
+		}
+		var err error
+		if a.Regexes, err = compileAll(gl.Regexes); err != nil {
+			return nil, fmt.Errorf("invalid regex: %w", err)
+		}
+		if a.Paths, err = compileAll(gl.Paths); err != nil {
+			return nil, fmt.Errorf("invalid path: %w", err)
+		}
+		out = append(out, a)
+	}
+	return out, nil
+}
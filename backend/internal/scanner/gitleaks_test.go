@@ -0,0 +1,210 @@
+package scanner
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeGitleaksConfig writes files into a temp dir and returns the path of
+// the first one.
+func writeGitleaksConfig(t *testing.T, files ...[2]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join(dir, f[0]), []byte(f[1]), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return filepath.Join(dir, files[0][0])
+}
+
+func gitleaksRuleByID(t *testing.T, rules []*Rule, id string) *Rule {
+	t.Helper()
+	for _, r := range rules {
+		if r.ID == id {
+			return r
+		}
+	}
+	t.Fatalf("rule %s not imported", id)
+	return nil
+}
+
+func TestGitleaksSecretGroup(t *testing.T) {
+	path := writeGitleaksConfig(t, [2]string{".gitleaks.toml", `
+[[rules]]
+id = "internal-token"
+regex = '''(?i)internal_token\s*=\s*"([a-z0-9]{24})"'''
+entropy = 3.5
+
+[[rules]]
+id = "optional-prefix"
+regex = '''(?:(svc)-)?itk_([a-z0-9]{24})'''
+
+[[rules]]
+id = "second-group"
+regex = '''(itk)_([a-z0-9]{24})'''
+secretGroup = 2
+
+[[rules]]
+id = "no-groups"
+regex = '''itk_[a-z0-9]{24}'''
+`})
+	rules, err := ReadGitleaksConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const secret = "q8zx3lp0vn7rt2yb5kc9hd4w"
+	cases := []struct {
+		rule, content, want string
+	}{
+		// no secretGroup: the first non-empty group, as in gitleaks, so the
+		// entropy check sees the secret rather than the key name
+		{"internal-token", `internal_token = "` + secret + `"`, secret},
+		// an optional group that didn't take part is skipped
+		{"optional-prefix", "itk_" + secret, secret},
+		{"optional-prefix", "svc-itk_" + secret, "svc"},
+		{"second-group", "itk_" + secret, secret},
+		{"no-groups", "itk_" + secret, "itk_" + secret},
+	}
+	for _, tc := range cases {
+		findings, _ := matchRule(gitleaksRuleByID(t, rules, tc.rule), "", tc.content)
+		if len(findings) != 1 || findings[0].Value != tc.want {
+			t.Errorf("%s on %q: got %+v, want value %q", tc.rule, tc.content, findings, tc.want)
+			continue
+		}
+		if f := findings[0]; tc.content[f.Start:f.End] != tc.want {
+			t.Errorf("%s: offsets point at %q", tc.rule, tc.content[f.Start:f.End])
+		}
+	}
+
+	// a low-entropy secret fails the rule's entropy check on the group alone
+	if findings, _ := matchRule(gitleaksRuleByID(t, rules, "internal-token"), "", `internal_token = "aaaaaaaaaaaaaaaaaaaaaaaa"`); len(findings) != 0 {
+		t.Errorf("low-entropy secret reported: %+v", findings)
+	}
+
+	bad := writeGitleaksConfig(t, [2]string{".gitleaks.toml", `
+[[rules]]
+id = "bad-group"
+regex = '''(itk)_[a-z0-9]{24}'''
+secretGroup = 2
+`})
+	if _, err := ReadGitleaksConfig(bad); err == nil || !strings.Contains(err.Error(), "secretGroup") {
+		t.Errorf("got %v, want a secretGroup range error", err)
+	}
+}
+
+func TestGitleaksAllowlists(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+	path := writeGitleaksConfig(t, [2]string{".gitleaks.toml", `
+[extend]
+useDefault = true
+
+[allowlist]
+description = "global"
+stopwords = ["sandbox"]
+
+[[allowlists]]
+description = "fixtures"
+paths = ['''^fixtures/''']
+
+[[rules]]
+id = "itk"
+regex = '''itk_[a-z0-9]{24}'''
+  [[rules.allowlists]]
+  description = "per-rule"
+  regexes = ['''_test[a-z0-9]{20}''']
+
+[[rules]]
+id = "otk"
+regex = '''otk_[a-z0-9]{24}'''
+
+[[rules]]
+id = "path-only"
+path = '''\.pem$'''
+`})
+	rules, err := ReadGitleaksConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("got %d rules, want itk and otk with the path-only rule skipped", len(rules))
+	}
+	itk, otk := gitleaksRuleByID(t, rules, "itk"), gitleaksRuleByID(t, rules, "otk")
+	const secret = "q8zx3lp0vn7rt2yb5kc9hd4w"
+	cases := []struct {
+		name    string
+		rule    *Rule
+		path    string
+		content string
+		found   bool
+	}{
+		{"reported", itk, "app/config.go", "itk_" + secret, true},
+		{"per-rule regex", itk, "app/config.go", "itk_test" + secret[:20], false},
+		{"per-rule regex doesn't cover other rules", otk, "app/config.go", "otk_test" + secret[:20], true},
+		{"global stopword", otk, "app/config.go", "otk_sandbox" + secret[:17], false},
+		{"global path-only allowlist", itk, "fixtures/tokens.txt", "itk_" + secret, false},
+		{"global path-only allowlist, other rule", otk, "fixtures/tokens.txt", "otk_" + secret, false},
+		{"path outside the allowlist", otk, "src/fixtures/tokens.txt", "otk_" + secret, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			findings, _ := matchRule(tc.rule, tc.path, tc.content)
+			if got := len(findings) == 1; got != tc.found {
+				t.Errorf("reported = %v, want %v: %+v", got, tc.found, findings)
+			}
+		})
+	}
+}
+
+func TestGitleaksExtend(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+	path := writeGitleaksConfig(t,
+		[2]string{"child.toml", `
+[extend]
+path = "base.toml"
+useDefault = true
+
+[allowlist]
+paths = ['''^vendor/''']
+
+[[rules]]
+id = "itk"
+regex = '''itk_[a-z0-9]{32}'''
+`},
+		[2]string{"base.toml", `
+[[rules]]
+id = "itk"
+regex = '''itk_[a-z0-9]{24}'''
+
+[[rules]]
+id = "otk"
+regex = '''otk_[a-z0-9]{24}'''
+`})
+	rules, err := ReadGitleaksConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("got %d rules, want 2", len(rules))
+	}
+	// the child's rule replaces the base's one with the same id
+	if got := gitleaksRuleByID(t, rules, "itk").Regex.String(); !strings.Contains(got, "{32}") {
+		t.Errorf("itk regex = %s, want the child's", got)
+	}
+	// the child's global allowlist covers inherited rules too
+	otk := gitleaksRuleByID(t, rules, "otk")
+	if findings, _ := matchRule(otk, "vendor/lib.go", "otk_q8zx3lp0vn7rt2yb5kc9hd4w"); len(findings) != 0 {
+		t.Errorf("inherited rule ignored the global allowlist: %+v", findings)
+	}
+
+	loop := writeGitleaksConfig(t, [2]string{"a.toml", "[extend]\npath = \"a.toml\"\n"})
+	if _, err := ReadGitleaksConfig(loop); err == nil || !strings.Contains(err.Error(), "extend chain") {
+		t.Errorf("got %v, want an extend depth error", err)
+	}
+}
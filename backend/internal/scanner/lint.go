@@ -0,0 +1,219 @@
+package scanner
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+)
+
+// Limits for LintRegex. Go's RE2 engine never backtracks, but match cost
+// still grows with the size of the compiled program, and nested unbounded
+// repeats are almost always a mistake carried over from backtracking
+// engines.
+const (
+	lintMaxProgram = 3000
+	lintMaxRepeat  = 256
+	// lintWorstCase is the size of the synthetic input matchCost runs on
+	// and lintMaxMatchCost the instructions per input byte it may take.
+	lintWorstCase    = 4 << 10
+	lintMaxMatchCost = 400
+)
+
+// LintRegex rejects rule patterns that look catastrophic: nested unbounded
+// repeats such as (a+)+ or (\w+\s?)*, repeats of something that can match
+// empty, huge bounded repeats and oversized programs. It then measures the
+// match cost on a synthetic worst-case input built from the pattern's own
+// characters. The cost is counted in NFA steps rather than timed, so the
+// result doesn't depend on machine load.
+func LintRegex(re *regexp.Regexp) error {
+	tree, err := syntax.Parse(re.String(), syntax.Perl)
+	if err != nil {
+		return err
+	}
+	if err := lintTree(tree); err != nil {
+		return err
+	}
+	prog, err := syntax.Compile(tree.Simplify())
+	if err != nil {
+		return err
+	}
+	if n := len(prog.Inst); n > lintMaxProgram {
+		return fmt.Errorf("regex compiles to %d instructions, limit is %d", n, lintMaxProgram)
+	}
+	if cost := matchCost(prog, worstCaseInput(tree, lintWorstCase)); cost > lintMaxMatchCost {
+		return fmt.Errorf("regex costs %d steps per byte on a %d KiB worst-case input, limit is %d", cost, lintWorstCase>>10, lintMaxMatchCost)
+	}
+	return nil
+}
+
+func lintTree(re *syntax.Regexp) error {
+	switch re.Op {
+	case syntax.OpStar, syntax.OpPlus, syntax.OpRepeat:
+		if re.Op == syntax.OpRepeat && (re.Max > lintMaxRepeat || re.Min > lintMaxRepeat) {
+			return fmt.Errorf("repeat {%d,%d} in %s exceeds %d", re.Min, re.Max, re, lintMaxRepeat)
+		}
+		unbounded := re.Op != syntax.OpRepeat || re.Max == -1
+		inner := unwrapCapture(re.Sub[0])
+		if unbounded && isUnboundedRepeat(inner) {
+			return fmt.Errorf("nested quantifier in %s", re)
+		}
+		if unbounded && matchesEmpty(inner) {
+			return fmt.Errorf("repeat of an expression that can match empty in %s", re)
+		}
+	}
+	for _, sub := range re.Sub {
+		if err := lintTree(sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func unwrapCapture(re *syntax.Regexp) *syntax.Regexp {
+	for re.Op == syntax.OpCapture {
+		re = re.Sub[0]
+	}
+	return re
+}
+
+// isUnboundedRepeat reports whether re, repeated, would nest an unbounded
+// repeat: re is one, an alternative of re is one, or re is a sequence whose
+// other parts can all match empty, as in (\w+\s?)*. A sequence with a
+// required separator, like ([a-z]+\.)+, is left alone.
+func isUnboundedRepeat(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpStar, syntax.OpPlus:
+		return true
+	case syntax.OpRepeat:
+		return re.Max == -1
+	case syntax.OpCapture:
+		return isUnboundedRepeat(re.Sub[0])
+	case syntax.OpAlternate:
+		for _, sub := range re.Sub {
+			if isUnboundedRepeat(sub) {
+				return true
+			}
+		}
+	case syntax.OpConcat:
+		repeat := false
+		for _, sub := range re.Sub {
+			switch {
+			case !repeat && isUnboundedRepeat(sub):
+				repeat = true
+			case !matchesEmpty(sub):
+				return false
+			}
+		}
+		return repeat
+	}
+	return false
+}
+
+// matchesEmpty reports whether re can match the empty string.
+func matchesEmpty(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpEmptyMatch, syntax.OpStar, syntax.OpQuest:
+		return true
+	case syntax.OpRepeat:
+		return re.Min == 0 || matchesEmpty(re.Sub[0])
+	case syntax.OpPlus, syntax.OpCapture:
+		return matchesEmpty(re.Sub[0])
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if !matchesEmpty(sub) {
+				return false
+			}
+		}
+		return true
+	case syntax.OpAlternate:
+		for _, sub := range re.Sub {
+			if matchesEmpty(sub) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchCost runs prog as an unanchored NFA over input, the way Go's regexp
+// falls back to when it can't use a faster matcher, and returns the
+// instructions executed per input byte. Zero-width assertions are assumed
+// to pass, which can only overstate the cost.
+func matchCost(prog *syntax.Prog, input string) int {
+	if input == "" {
+		return 0
+	}
+	seen := make([]int, len(prog.Inst))
+	gen, steps := 0, 0
+	var add func(list []uint32, pc uint32) []uint32
+	add = func(list []uint32, pc uint32) []uint32 {
+		if seen[pc] == gen {
+			return list
+		}
+		seen[pc] = gen
+		steps++
+		inst := &prog.Inst[pc]
+		switch inst.Op {
+		case syntax.InstAlt, syntax.InstAltMatch:
+			list = add(list, inst.Out)
+			return add(list, inst.Arg)
+		case syntax.InstCapture, syntax.InstNop, syntax.InstEmptyWidth:
+			return add(list, inst.Out)
+		case syntax.InstMatch, syntax.InstFail:
+			return list
+		}
+		return append(list, pc)
+	}
+	gen++
+	cur := add(nil, uint32(prog.Start))
+	var next []uint32
+	for _, r := range input {
+		gen++
+		next = next[:0]
+		for _, pc := range cur {
+			steps++
+			if prog.Inst[pc].MatchRune(r) {
+				next = add(next, prog.Inst[pc].Out)
+			}
+		}
+		next = add(next, uint32(prog.Start))
+		cur, next = next, cur
+	}
+	return steps / len(input)
+}
+
+// worstCaseInput builds n bytes that keep the regex busy: the pattern's
+// literals and a few characters from each class, repeated so partial
+// matches start everywhere but rarely complete.
+func worstCaseInput(re *syntax.Regexp, n int) string {
+	var alphabet strings.Builder
+	var walk func(*syntax.Regexp)
+	walk = func(re *syntax.Regexp) {
+		switch re.Op {
+		case syntax.OpLiteral:
+			alphabet.WriteString(string(re.Rune))
+		case syntax.OpCharClass:
+			for i := 0; i+1 < len(re.Rune) && i < 8; i += 2 {
+				alphabet.WriteRune(re.Rune[i])
+				if re.Rune[i+1] != re.Rune[i] && re.Rune[i+1] < 0x80 {
+					alphabet.WriteRune(re.Rune[i+1])
+				}
+			}
+		}
+		for _, sub := range re.Sub {
+			walk(sub)
+		}
+	}
+	walk(re)
+	unit := alphabet.String()
+	if unit == "" {
+		unit = "a"
+	}
+	var b strings.Builder
+	b.Grow(n + len(unit))
+	for b.Len() < n {
+		b.WriteString(unit)
+	}
+	return b.String()[:n]
+}
@@ -0,0 +1,42 @@
+package scanner
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestLintRegex(t *testing.T) {
+	rejected := map[string]string{
+		`(a+)+b`:              "nested quantifier",
+		`(?:\w*)*=`:           "nested quantifier",
+		`((a|b)+)+`:           "nested quantifier",
+		`(\w+\s?)*`:           "nested quantifier",
+		`(a|)+x`:              "can match empty",
+		`(?:x?y?)*z`:          "can match empty",
+		`key-[0-9a-f]{1,500}`: "exceeds",
+		// passes the structural checks, but every position keeps hundreds
+		// of NFA threads alive
+		`[a-z]{0,250}[a-z]{0,250}`: "steps per byte",
+	}
+	rejected[strings.Repeat(`[a-z]{200}-`, 20)] = "instructions"
+	for pattern, reason := range rejected {
+		err := LintRegex(regexp.MustCompile(pattern))
+		if err == nil || !strings.Contains(err.Error(), reason) {
+			t.Errorf("%s: got %v, want an error containing %q", pattern, err, reason)
+		}
+	}
+	for _, pattern := range []string{`itk_[a-z0-9]{32}`, `(?:[a-z]+\.)+internal`, `(?:x(?:[a-z]+)y?)+`, `(?i)token\s*[:=]\s*['"]?([A-Za-z0-9]{20,})`} {
+		if err := LintRegex(regexp.MustCompile(pattern)); err != nil {
+			t.Errorf("%s: unexpected lint error: %v", pattern, err)
+		}
+	}
+}
+
+func TestBuiltinRulesPassLint(t *testing.T) {
+	for _, r := range builtinRules {
+		if err := LintRegex(r.Regex); err != nil {
+			t.Errorf("%s: %v", r.ID, err)
+		}
+	}
+}
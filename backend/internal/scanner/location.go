@@ -0,0 +1,104 @@
+package scanner
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// snippetContext is the number of bytes kept on each side of a match when
+// building a finding's snippet.
+const snippetContext = 32
+
+// locate fills in Line, Column and Snippet from the Start/End byte offsets of
+// each finding. Lines and columns are 1-based; columns count runes. Findings
+// that already have a line (notebook cells) are left alone.
+func locate(content string, findings []Finding) {
+	lineStarts := []int{0}
+	for i := 0; i < len(content); i++ {
+		if content[i] == '\n' {
+			lineStarts = append(lineStarts, i+1)
+		}
+	}
+	secrets := make([]span, 0, len(findings))
+	for _, f := range findings {
+		if f.Line == 0 {
+			secrets = append(secrets, span{f.Start, f.End})
+		}
+	}
+	sort.Slice(secrets, func(i, j int) bool { return secrets[i].start < secrets[j].start })
+	for i := range findings {
+		f := &findings[i]
+		if f.Line != 0 {
+			continue
+		}
+		line := sort.Search(len(lineStarts), func(j int) bool { return lineStarts[j] > f.Start }) - 1
+		f.Line = line + 1
+		f.Column = utf8.RuneCountInString(content[lineStarts[line]:f.Start]) + 1
+		f.Snippet = snippet(content, f.Start, f.End, secrets)
+	}
+}
+
+// snippet returns the text around content[start:end] on the same line with
+// every secret in the window redacted. secrets holds the spans of all
+// findings in content, this one included, sorted by start; a secret cut off
+// by the window edge is masked without its prefix.
+func snippet(content string, start, end int, secrets []span) string {
+	from := start - snippetContext
+	if nl := strings.LastIndexByte(content[:start], '\n'); nl >= from {
+		from = nl + 1
+	}
+	if from < 0 {
+		from = 0
+	}
+	to := end + snippetContext
+	if nl := strings.IndexByte(content[end:], '\n'); nl >= 0 && end+nl < to {
+		to = end + nl
+	}
+	if to > len(content) {
+		to = len(content)
+	}
+	for from > 0 && from < start && !utf8.RuneStart(content[from]) {
+		from++
+	}
+	for to < len(content) && to > end && !utf8.RuneStart(content[to]) {
+		to--
+	}
+	var b strings.Builder
+	pos := from
+	for _, s := range secrets {
+		if s.end <= pos || s.start >= to {
+			continue
+		}
+		lo, hi := max(s.start, pos), min(s.end, to)
+		b.WriteString(content[pos:lo])
+		if lo == s.start && hi == s.end {
+			b.WriteString(Redact(content[lo:hi]))
+		} else {
+			b.WriteString(strings.Repeat("*", 8))
+		}
+		pos = hi
+	}
+	b.WriteString(content[pos:to])
+	return strings.TrimSpace(b.String())
+}
+
+// Redact masks a secret value, keeping a short prefix so reviewers can tell
+// which credential it was.
+func Redact(value string) string {
+	keep := 4
+	if len(value) <= 8 {
+		keep = 0
+	}
+	return value[:keep] + strings.Repeat("*", 8)
+}
+
+// lineAt returns the full line(s) containing content[start:end].
+func lineAt(content string, start, end int) string {
+	from := strings.LastIndexByte(content[:start], '\n') + 1
+	to := strings.IndexByte(content[end:], '\n')
+	if to < 0 {
+		return content[from:]
+	}
+	return content[from : end+to]
+}
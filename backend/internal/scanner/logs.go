@@ -0,0 +1,163 @@
+package scanner
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// fieldSpan is the byte range of one field's value within a log line.
+type fieldSpan struct {
+	name       string
+	start, end int
+}
+
+// isLog reports whether content should be treated as a log file: by
+// extension, or for unnamed content when it starts with a JSON object line.
+func isLog(path string, content string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".log", ".jsonl", ".ndjson":
+		return true
+	}
+	if path != "" {
+		return false
+	}
+	first, _, _ := strings.Cut(content, "\n")
+	first = strings.TrimSpace(first)
+	return strings.HasPrefix(first, "{") && strings.Contains(content, "\n") && json.Valid([]byte(first))
+}
+
+// annotateLogFields sets LogField on findings inside a JSON-lines or logfmt
+// record so a ticket can say which field of which line leaked the secret.
+func annotateLogFields(content string, findings []Finding) {
+	lineStart := 0
+	var fields []fieldSpan
+	parsedAt := -1
+	for i := range findings {
+		f := &findings[i]
+		ls := strings.LastIndexByte(content[:f.Start], '\n') + 1
+		if ls != parsedAt {
+			le := strings.IndexByte(content[ls:], '\n')
+			if le < 0 {
+				le = len(content) - ls
+			}
+			line := content[ls : ls+le]
+			if t := strings.TrimSpace(line); strings.HasPrefix(t, "{") {
+				fields = jsonFields(line)
+			} else {
+				fields = logfmtFields(line)
+			}
+			parsedAt, lineStart = ls, ls
+		}
+		for _, fs := range fields {
+			if f.Start-lineStart >= fs.start && f.Start-lineStart < fs.end {
+				f.LogField = fs.name
+				break
+			}
+		}
+	}
+}
+
+// jsonFields returns the spans of string and number values in a JSON line,
+// named by their dotted key path.
+func jsonFields(line string) []fieldSpan {
+	dec := json.NewDecoder(strings.NewReader(line))
+	out := []fieldSpan{}
+	// stack of container frames; for objects, key is the pending key
+	type frame struct {
+		object bool
+		key    string
+		index  int
+	}
+	stack := []frame{}
+	name := func() string {
+		parts := make([]string, 0, len(stack))
+		for _, fr := range stack {
+			if fr.object {
+				parts = append(parts, fr.key)
+			} else {
+				parts = append(parts, "["+strconv.Itoa(fr.index)+"]")
+			}
+		}
+		return strings.ReplaceAll(strings.Join(parts, "."), ".[", "[")
+	}
+	expectKey := false
+	for {
+		before := dec.InputOffset()
+		tok, err := dec.Token()
+		if err != nil {
+			return out
+		}
+		switch t := tok.(type) {
+		case json.Delim:
+			if t == '{' || t == '[' {
+				stack = append(stack, frame{object: t == '{'})
+				expectKey = t == '{'
+				continue
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if expectKey {
+				stack[len(stack)-1].key = t.(string)
+				expectKey = false
+				continue
+			}
+			if _, ok := t.(string); ok {
+				out = append(out, fieldSpan{name: name(), start: int(before), end: int(dec.InputOffset())})
+			}
+		}
+		// a value just finished: advance the enclosing container
+		if n := len(stack); n > 0 {
+			if stack[n-1].object {
+				expectKey = true
+			} else {
+				stack[n-1].index++
+			}
+		}
+	}
+}
+
+// logfmtFields returns key=value and key="quoted value" spans of a logfmt
+// line.
+func logfmtFields(line string) []fieldSpan {
+	out := []fieldSpan{}
+	i := 0
+	for i < len(line) {
+		for i < len(line) && line[i] == ' ' {
+			i++
+		}
+		keyStart := i
+		for i < len(line) && line[i] != '=' && line[i] != ' ' {
+			i++
+		}
+		if i >= len(line) || line[i] != '=' || i == keyStart {
+			for i < len(line) && line[i] != ' ' {
+				i++
+			}
+			continue
+		}
+		key := line[keyStart:i]
+		i++
+		valStart := i
+		if i < len(line) && line[i] == '"' {
+			i++
+			for i < len(line) && line[i] != '"' {
+				if line[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			i++
+		} else {
+			for i < len(line) && line[i] != ' ' {
+				i++
+			}
+		}
+		if i > len(line) {
+			i = len(line)
+		}
+		out = append(out, fieldSpan{name: key, start: valStart, end: i})
+	}
+	return out
+}
@@ -0,0 +1,143 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// notebook is the subset of the Jupyter nbformat 4 schema we scan.
+type notebook struct {
+	NBFormat int            `json:"nbformat"`
+	Cells    []notebookCell `json:"cells"`
+}
+
+type notebookCell struct {
+	CellType string           `json:"cell_type"`
+	Source   multilineString  `json:"source"`
+	Outputs  []notebookOutput `json:"outputs"`
+}
+
+type notebookOutput struct {
+	OutputType string                     `json:"output_type"`
+	Text       multilineString            `json:"text"`
+	Data       map[string]json.RawMessage `json:"data"`
+	Traceback  []string                   `json:"traceback"`
+}
+
+// multilineString is nbformat's "string or list of strings" field.
+type multilineString string
+
+func (m *multilineString) UnmarshalJSON(b []byte) error {
+	var lines []string
+	if err := json.Unmarshal(b, &lines); err == nil {
+		*m = multilineString(strings.Join(lines, ""))
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	*m = multilineString(s)
+	return nil
+}
+
+// parseNotebook decodes content as a notebook when the path says .ipynb or
+// the JSON carries nbformat and cells.
+func parseNotebook(path string, content string) (notebook, bool) {
+	var nb notebook
+	isNB := strings.EqualFold(filepath.Ext(path), ".ipynb")
+	if !isNB && (path != "" || !strings.Contains(content, `"nbformat"`)) {
+		return nb, false
+	}
+	if err := json.Unmarshal([]byte(content), &nb); err != nil || nb.NBFormat == 0 {
+		return nb, false
+	}
+	return nb, true
+}
+
+// outputText is the human-readable text of a cell output: stream text,
+// text/plain and other text/* results, and error tracebacks. Images and
+// other binary mime types are skipped. Results are taken text/plain first,
+// then by mime type, so the text and its offsets are the same on every scan.
+func (o notebookOutput) outputText() string {
+	mimes := make([]string, 0, len(o.Data))
+	for mime := range o.Data {
+		if strings.HasPrefix(mime, "text/") || mime == "application/json" {
+			mimes = append(mimes, mime)
+		}
+	}
+	sort.Slice(mimes, func(i, j int) bool {
+		if (mimes[i] == "text/plain") != (mimes[j] == "text/plain") {
+			return mimes[i] == "text/plain"
+		}
+		return mimes[i] < mimes[j]
+	})
+	parts := []string{string(o.Text)}
+	for _, mime := range mimes {
+		raw := o.Data[mime]
+		var s multilineString
+		if json.Unmarshal(raw, &s) == nil {
+			parts = append(parts, string(s))
+		} else if mime == "application/json" {
+			parts = append(parts, string(raw))
+		}
+	}
+	parts = append(parts, strings.Join(o.Traceback, "\n"))
+	return strings.Join(parts, "\n")
+}
+
+// scanNotebook scans each cell's source and outputs separately so findings
+// point at a cell rather than an offset into the notebook JSON. A secret
+// repeated across one cell's outputs, as when a result is rendered both as
+// text/plain and text/html, is reported once.
+func scanNotebook(ctx context.Context, path string, nb notebook) (Report, error) {
+	report := Report{Findings: []Finding{}}
+	add := func(cell int, part string, text string) error {
+		if strings.TrimSpace(text) == "" {
+			return nil
+		}
+		r, err := scanText(ctx, path, text, false)
+		if err != nil {
+			return err
+		}
+		locate(text, r.Findings)
+		seen := map[[2]string]bool{}
+		for _, f := range r.Findings {
+			if part == "output" {
+				key := [2]string{f.Type, f.Value}
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+			}
+			idx := cell
+			f.Cell = &idx
+			f.CellPart = part
+			report.Findings = append(report.Findings, f)
+		}
+		for _, sup := range r.Suppressed {
+			idx := cell
+			sup.Finding.Cell = &idx
+			sup.Finding.CellPart = part
+			report.Suppressed = append(report.Suppressed, sup)
+		}
+		report.Allowlisted += r.Allowlisted
+		return nil
+	}
+	for i, c := range nb.Cells {
+		if err := add(i, "source", string(c.Source)); err != nil {
+			return report, err
+		}
+		outputs := make([]string, 0, len(c.Outputs))
+		for _, o := range c.Outputs {
+			outputs = append(outputs, o.outputText())
+		}
+		if err := add(i, "output", strings.Join(outputs, "\n")); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,98 @@
+package scanner
+
+import (
+	"log"
+	"sort"
+)
+
+// Priorities of findings that don't come from a rule. Rules default to 0,
+// so any rule outranks these generic detectors on the same bytes.
+const (
+	genericRulePriority    = -10
+	sensitiveKeyPriority   = -15
+	entropyFindingPriority = -20
+)
+
+// Suppressed is a finding dropped because a higher-priority finding covered
+// some of the same bytes. It is kept on the report for debugging rules.
+type Suppressed struct {
+	Finding Finding `json:"finding"`
+	// By is the Type of the finding that was kept instead.
+	By string `json:"by"`
+}
+
+var severityRank = map[string]int{"critical": 4, "high": 3, "medium": 2, "low": 1}
+
+// findingPriority ranks f for overlap resolution by its rule's Priority,
+// falling back to the fixed priorities of the non-rule detectors.
+func findingPriority(f Finding) int {
+	switch f.Type {
+	case "HighEntropyHex", "HighEntropyBase64":
+		return entropyFindingPriority
+	case "SensitiveKeyValue":
+		return sensitiveKeyPriority
+	}
+	if r := active.byID[f.Type]; r != nil {
+		return r.Priority
+	}
+	return 0
+}
+
+// outranks reports whether a should be kept over b when they overlap: higher
+// rule priority first, then a verified format, higher severity, a longer
+// match and finally the rule id so the choice never depends on input order.
+func outranks(a, b Finding) bool {
+	if pa, pb := findingPriority(a), findingPriority(b); pa != pb {
+		return pa > pb
+	}
+	if a.VerifiedFormat != b.VerifiedFormat {
+		return a.VerifiedFormat
+	}
+	if sa, sb := severityRank[a.Severity], severityRank[b.Severity]; sa != sb {
+		return sa > sb
+	}
+	if la, lb := a.End-a.Start, b.End-b.Start; la != lb {
+		return la > lb
+	}
+	if a.Type != b.Type {
+		return a.Type < b.Type
+	}
+	return a.Start < b.Start
+}
+
+// resolveOverlaps keeps one finding per group of overlapping findings, the
+// one that outranks the others, and returns the survivors sorted by
+// position. Findings it drops are returned as suppressed.
+func resolveOverlaps(findings []Finding) ([]Finding, []Suppressed) {
+	ranked := make([]Finding, len(findings))
+	copy(ranked, findings)
+	sort.SliceStable(ranked, func(i, j int) bool { return outranks(ranked[i], ranked[j]) })
+
+	kept := make([]Finding, 0, len(ranked))
+	var suppressed []Suppressed
+	for _, f := range ranked {
+		if i := overlappingFinding(kept, f); i >= 0 {
+			log.Printf("scanner: %s at %d-%d suppressed by overlapping %s", f.Type, f.Start, f.End, kept[i].Type)
+			suppressed = append(suppressed, Suppressed{Finding: f, By: kept[i].Type})
+			continue
+		}
+		kept = append(kept, f)
+	}
+	sortByPosition(kept)
+	sort.SliceStable(suppressed, func(i, j int) bool { return suppressed[i].Finding.Start < suppressed[j].Finding.Start })
+	return kept, suppressed
+}
+
+// sortByPosition orders findings by start offset, then end offset and type.
+func sortByPosition(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Start != b.Start {
+			return a.Start < b.Start
+		}
+		if a.End != b.End {
+			return a.End < b.End
+		}
+		return a.Type < b.Type
+	})
+}
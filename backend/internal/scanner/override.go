@@ -0,0 +1,131 @@
+package scanner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Override changes how one rule (or detector) is reported for a single scan,
+// typically for one repository or channel.
+type Override struct {
+	// Disabled drops the rule's findings.
+	Disabled bool
+	// Severity, when set, replaces the rule's severity.
+	Severity string
+}
+
+// ValidateOverride checks that ruleID is a known rule or detector and that
+// the severity, if set, is one of critical, high, medium or low. It returns
+// the severity lower-cased.
+func ValidateOverride(ruleID, severity string) (string, error) {
+	if _, ok := LookupRule(ruleID); !ok {
+		return "", fmt.Errorf("unknown rule %q", ruleID)
+	}
+	sev := strings.ToLower(severity)
+	if sev != "" && !severities[sev] {
+		return "", fmt.Errorf("invalid severity %q, want critical, high, medium or low", severity)
+	}
+	return sev, nil
+}
+
+// ApplyOverrides returns report with overrides, keyed by finding type,
+// applied. Findings of disabled rules are dropped and counted in
+// Report.Disabled. A finding suppressed only because a disabled rule won the
+// overlap is reported again, unless it overlaps another kept finding.
+// Severity overrides keep the one-step downgrade of matches that failed
+// format validation.
+func ApplyOverrides(report Report, overrides map[string]Override) Report {
+	if len(overrides) == 0 {
+		return report
+	}
+	// start from a copy so fields added to Report later carry over
+	out := report
+	out.Findings = make([]Finding, 0, len(report.Findings))
+	out.Suppressed = nil
+	for _, f := range report.Findings {
+		if overrides[f.Type].Disabled {
+			out.Disabled++
+			continue
+		}
+		out.Findings = append(out.Findings, overrideSeverity(f, overrides))
+	}
+	restored := false
+	for _, sup := range report.Suppressed {
+		f := sup.Finding
+		switch {
+		case overrides[f.Type].Disabled:
+		case overrides[sup.By].Disabled && !overlapsInRegion(out.Findings, f):
+			out.Findings = append(out.Findings, overrideSeverity(f, overrides))
+			restored = true
+		default:
+			out.Suppressed = append(out.Suppressed, sup)
+		}
+	}
+	if restored {
+		sortByRegion(out.Findings)
+	}
+	return out
+}
+
+func overrideSeverity(f Finding, overrides map[string]Override) Finding {
+	sev := overrides[f.Type].Severity
+	if sev == "" {
+		return f
+	}
+	if r := active.byID[f.Type]; r != nil && r.DowngradeInvalid && f.Severity != r.Severity {
+		sev = downgrade(sev)
+	}
+	f.Severity = sev
+	return f
+}
+
+// overlapsInRegion is overlappingFinding limited to findings from the same
+// notebook cell part, whose offsets share an origin.
+func overlapsInRegion(findings []Finding, f Finding) bool {
+	for _, g := range findings {
+		if sameRegion(g, f) && findingsOverlap(g, f) {
+			return true
+		}
+	}
+	return false
+}
+
+func sameRegion(a, b Finding) bool {
+	if (a.Cell == nil) != (b.Cell == nil) {
+		return false
+	}
+	return a.Cell == nil || (*a.Cell == *b.Cell && a.CellPart == b.CellPart)
+}
+
+// sortByRegion is sortByPosition that keeps notebook findings in cell
+// order, source before output.
+func sortByRegion(findings []Finding) {
+	region := func(f Finding) (int, int) {
+		if f.Cell == nil {
+			return -1, 0
+		}
+		if f.CellPart == "output" {
+			return *f.Cell, 1
+		}
+		return *f.Cell, 0
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		ca, pa := region(a)
+		cb, pb := region(b)
+		if ca != cb {
+			return ca < cb
+		}
+		if pa != pb {
+			return pa < pb
+		}
+		if a.Start != b.Start {
+			return a.Start < b.Start
+		}
+		if a.End != b.End {
+			return a.End < b.End
+		}
+		return a.Type < b.Type
+	})
+}
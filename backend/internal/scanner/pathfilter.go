@@ -0,0 +1,196 @@
+package scanner
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultExcludes are the gitignore-style patterns of files that are
+// skipped unless a filter re-includes them with a "!" pattern: vendored
+// dependencies, test fixtures, lockfiles and minified or generated bundles.
+var DefaultExcludes = []string{
+	"vendor/",
+	"node_modules/",
+	"testdata/",
+	"package-lock.json",
+	"yarn.lock",
+	"pnpm-lock.yaml",
+	"go.sum",
+	"Cargo.lock",
+	"Gemfile.lock",
+	"composer.lock",
+	"poetry.lock",
+	"Pipfile.lock",
+	"*.min.js",
+	"*.min.css",
+	"*.map",
+}
+
+// PathFilter decides which files are scanned, from gitignore-style pattern
+// lists. A file is skipped when it matches the exclude patterns, or when
+// there are include patterns and it matches none of them. Within a list the
+// last matching pattern wins and a leading "!" negates, so "!testdata/keys/"
+// re-includes a directory an earlier pattern excluded.
+type PathFilter struct {
+	include []pathPattern
+	exclude []pathPattern
+}
+
+// pathPattern is one compiled gitignore line.
+type pathPattern struct {
+	re     *regexp.Regexp
+	negate bool
+	// dirOnly patterns (trailing "/") only match directories, so they
+	// apply to the files under a matching directory but not to a file with
+	// the same name.
+	dirOnly bool
+}
+
+// NewPathFilter compiles include and exclude pattern lists. Blank lines
+// and "#" comments are ignored, so the lines of a .gitignore file can be
+// passed as they are.
+func NewPathFilter(include, exclude []string) (*PathFilter, error) {
+	var pf PathFilter
+	var err error
+	if pf.include, err = compilePathPatterns(include); err != nil {
+		return nil, err
+	}
+	if pf.exclude, err = compilePathPatterns(exclude); err != nil {
+		return nil, err
+	}
+	return &pf, nil
+}
+
+// Skip returns why the file at path should not be scanned, or "" when it
+// should be. Archive members ("upload.zip!/dir/file") are matched by their
+// path inside the archive. Content without a path is never skipped.
+func (pf *PathFilter) Skip(path string) string {
+	if pf == nil {
+		return ""
+	}
+	path = normalizeFilterPath(path)
+	if path == "" {
+		return ""
+	}
+	if len(pf.include) > 0 && !matchPathPatterns(pf.include, path) {
+		return "not included by path filter"
+	}
+	if matchPathPatterns(pf.exclude, path) {
+		return "excluded by path filter"
+	}
+	return ""
+}
+
+func normalizeFilterPath(path string) string {
+	if i := strings.LastIndex(path, "!/"); i >= 0 {
+		path = path[i+2:]
+	}
+	path = strings.ReplaceAll(path, `\`, "/")
+	for strings.HasPrefix(path, "./") {
+		path = path[2:]
+	}
+	return strings.TrimLeft(path, "/")
+}
+
+// matchPathPatterns reports whether the last pattern matching path, or one
+// of the directories containing it, is a positive one.
+func matchPathPatterns(patterns []pathPattern, path string) bool {
+	dirs := []string{}
+	for i := 0; i < len(path); i++ {
+		if path[i] == '/' {
+			dirs = append(dirs, path[:i])
+		}
+	}
+	matched := false
+	for _, p := range patterns {
+		hit := !p.dirOnly && p.re.MatchString(path)
+		for _, d := range dirs {
+			if hit {
+				break
+			}
+			hit = p.re.MatchString(d)
+		}
+		if hit {
+			matched = !p.negate
+		}
+	}
+	return matched
+}
+
+func compilePathPatterns(lines []string) ([]pathPattern, error) {
+	out := make([]pathPattern, 0, len(lines))
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		orig := line
+		p := pathPattern{}
+		if strings.HasPrefix(line, "!") {
+			p.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			p.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		// a pattern with a slash is relative to the root; one without
+		// matches a name at any depth
+		anchored := strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+		if line == "" {
+			return nil, fmt.Errorf("invalid path pattern %q", orig)
+		}
+		expr, err := globToRegexp(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid path pattern %q: %w", orig, err)
+		}
+		if !anchored {
+			expr = "(?:.*/)?" + expr
+		}
+		if p.re, err = regexp.Compile("^" + expr + "$"); err != nil {
+			return nil, fmt.Errorf("invalid path pattern %q: %w", orig, err)
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// globToRegexp translates gitignore glob syntax: "*" and "?" stay within
+// one path segment, "**" spans segments and [...] is a character class.
+func globToRegexp(glob string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				return "", fmt.Errorf("unterminated character class")
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(glob):
+			i++
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		default:
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,75 @@
+package scanner
+
+import "testing"
+
+func TestPathFilter(t *testing.T) {
+	tests := []struct {
+		name             string
+		include, exclude []string
+		path             string
+		want             string
+	}{
+		{"no path", nil, DefaultExcludes, "", ""},
+		{"plain file", nil, DefaultExcludes, "cmd/server/main.go", ""},
+		{"vendor dir", nil, DefaultExcludes, "vendor/github.com/x/y.go", "excluded by path filter"},
+		{"nested node_modules", nil, DefaultExcludes, "web/node_modules/a/index.js", "excluded by path filter"},
+		{"dir-only pattern skips file", nil, []string{"build/"}, "build", ""},
+		{"lockfile at depth", nil, DefaultExcludes, "services/api/go.sum", "excluded by path filter"},
+		{"minified bundle", nil, DefaultExcludes, "static/app.min.js", "excluded by path filter"},
+		{"archive member", nil, DefaultExcludes, "upload.zip!/vendor/lib.go", "excluded by path filter"},
+		{"windows separators", nil, DefaultExcludes, `app\testdata\key.pem`, "excluded by path filter"},
+		{"negation re-includes", nil, []string{"testdata/", "!testdata/keys/"}, "testdata/keys/id_rsa", ""},
+		{"negation keeps others", nil, []string{"testdata/", "!testdata/keys/"}, "testdata/other.txt", "excluded by path filter"},
+		{"anchored", nil, []string{"/config/*.yaml"}, "deploy/config/a.yaml", ""},
+		{"anchored match", nil, []string{"/config/*.yaml"}, "config/a.yaml", "excluded by path filter"},
+		{"star stays in segment", nil, []string{"docs/*.md"}, "docs/api/x.md", ""},
+		{"double star", nil, []string{"docs/**/*.md"}, "docs/api/v1/x.md", "excluded by path filter"},
+		{"character class", nil, []string{"*.[ch]"}, "src/x.h", "excluded by path filter"},
+		{"comments ignored", nil, []string{"# vendor/", ""}, "vendor/x.go", ""},
+		{"include", []string{"src/", "*.env"}, nil, "src/app.py", ""},
+		{"include env", []string{"src/", "*.env"}, nil, "deploy/prod.env", ""},
+		{"not included", []string{"src/", "*.env"}, nil, "docs/README.md", "not included by path filter"},
+		{"exclude wins over include", []string{"src/"}, []string{"*_test.go"}, "src/a_test.go", "excluded by path filter"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pf, err := NewPathFilter(tt.include, tt.exclude)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := pf.Skip(tt.path); got != tt.want {
+				t.Errorf("Skip(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathFilterInvalid(t *testing.T) {
+	for _, p := range []string{"[abc", "/", "!"} {
+		if _, err := NewPathFilter(nil, []string{p}); err == nil {
+			t.Errorf("expected an error for %q", p)
+		}
+	}
+}
+
+func TestRuleExtensions(t *testing.T) {
+	rules, err := parseRulesFile([]byte(`
+[[rules]]
+id = "TerraformToken"
+regex = '''tfx_[a-z0-9]{24}'''
+extensions = [".tf", ".TFVARS"]
+mustMatch = ["tfx_9f2kq8z3lp0vn7rt2yb5wm1x"]
+`), ".toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := rules[0]
+	for path, want := range map[string]bool{"main.tf": true, "prod.tfvars": true, "main.go": false, "": false} {
+		if got := r.appliesTo(path); got != want {
+			t.Errorf("appliesTo(%q) = %v, want %v", path, got, want)
+		}
+	}
+	if _, err := parseRulesFile([]byte("[[rules]]\nid = \"X\"\nregex = 'x_[0-9]{8}'\nextensions = [\"tf\"]\n"), ".toml"); err == nil {
+		t.Error("expected an error for an extension without a dot")
+	}
+}
@@ -0,0 +1,76 @@
+package scanner
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Workers caps the goroutines scanning at once: the calling goroutine plus
+// up to Workers-1 helpers, shared by every Parallel call in the process. A
+// scan nested inside another's task (a decoded layer, a bulk item) only
+// gets helpers that are idle, so nesting never multiplies the count. 1 makes
+// scanning fully sequential.
+var Workers = runtime.GOMAXPROCS(0)
+
+// parallelMinSize is the input size below which rules are evaluated inline;
+// for small payloads goroutine handoff costs more than the regexes.
+const parallelMinSize = 32 << 10
+
+// helpers counts the helper goroutines running across all Parallel calls.
+var helpers struct {
+	sync.Mutex
+	busy int
+}
+
+// acquireHelpers reserves up to want helper goroutines, as many as are
+// free under Workers.
+func acquireHelpers(want int) int {
+	helpers.Lock()
+	defer helpers.Unlock()
+	if free := Workers - 1 - helpers.busy; want > free {
+		want = free
+	}
+	if want < 0 {
+		want = 0
+	}
+	helpers.busy += want
+	return want
+}
+
+func releaseHelper() {
+	helpers.Lock()
+	helpers.busy--
+	helpers.Unlock()
+}
+
+// Parallel calls fn for every index in [0, n) and waits for them. The
+// calling goroutine works through the indexes itself, joined by whichever
+// helpers are free. Callers write results into a slice by index so the
+// output order does not depend on scheduling. Once ctx is done no further
+// indexes are handed out and ctx's error is returned.
+func Parallel(ctx context.Context, n int, fn func(i int)) error {
+	var next atomic.Int64
+	work := func() {
+		for ctx.Err() == nil {
+			i := int(next.Add(1) - 1)
+			if i >= n {
+				return
+			}
+			fn(i)
+		}
+	}
+	var wg sync.WaitGroup
+	for h := acquireHelpers(n - 1); h > 0; h-- {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer releaseHelper()
+			work()
+		}()
+	}
+	work()
+	wg.Wait()
+	return ctx.Err()
+}
@@ -0,0 +1,106 @@
+package scanner
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Inline allowlist pragmas. A comment on the line of a match holding the
+// word "nosecret" or "gitleaks:allow" suppresses it; "nosecret:JWT,GithubToken"
+// limits that to the named rules. A comment line holding only the pragma
+// covers the line after it instead. The pragma has to be a whole word inside
+// a comment, so "https://nosecret.example.com" in a string allows nothing.
+var pragmaPattern = regexp.MustCompile(`^(?:nosecret(?::([A-Za-z0-9_,-]+))?|gitleaks:allow)$`)
+
+// commentMarkers pairs the comment openers recognised on a pragma-only
+// line with the closer that may end it.
+var commentMarkers = [][2]string{{"#", ""}, {"//", ""}, {"--", ""}, {";", ""}, {"/*", "*/"}, {"<!--", "-->"}, {"rem ", ""}}
+
+// pragmaAllows reports whether a pragma on the line holding content[start]
+// or on the comment line just before it covers rule.
+func pragmaAllows(content string, start int, rule string) bool {
+	lineStart := strings.LastIndexByte(content[:start], '\n') + 1
+	lineEnd := strings.IndexByte(content[start:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(content)
+	} else {
+		lineEnd += start
+	}
+	if lineAllows(content[lineStart:lineEnd], rule) {
+		return true
+	}
+	if lineStart == 0 {
+		return false
+	}
+	prevStart := strings.LastIndexByte(content[:lineStart-1], '\n') + 1
+	pragma, ok := commentPragma(content[prevStart : lineStart-1])
+	return ok && wordAllows(pragma, rule)
+}
+
+// commentPragma returns the pragma of a comment line that holds nothing
+// else, such as "# nosecret" or "/* nosecret:JWT */".
+func commentPragma(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	for _, m := range commentMarkers {
+		if len(line) < len(m[0]) || !strings.EqualFold(line[:len(m[0])], m[0]) {
+			continue
+		}
+		body := strings.TrimSuffix(line[len(m[0]):], m[1])
+		body = strings.TrimSpace(body)
+		if pragmaPattern.MatchString(body) {
+			return body, true
+		}
+		return "", false
+	}
+	return "", false
+}
+
+// lineAllows reports whether a comment on line holds a pragma covering
+// rule. A comment starts at an opener at the start of the line or after
+// whitespace or the end of a statement, so "#" in a URL fragment and "//"
+// after a scheme don't count.
+func lineAllows(line, rule string) bool {
+	for _, m := range commentMarkers[:len(commentMarkers)-1] {
+		for from := 0; ; {
+			i := strings.Index(line[from:], m[0])
+			if i < 0 {
+				break
+			}
+			i += from
+			from = i + len(m[0])
+			if i > 0 && !strings.ContainsRune(" \t;,)]}'\"", rune(line[i-1])) {
+				continue
+			}
+			for _, word := range strings.Fields(line[from:]) {
+				word = strings.TrimSuffix(word, m[1])
+				if wordAllows(word, rule) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// wordAllows reports whether word is a pragma covering rule.
+func wordAllows(word, rule string) bool {
+	m := pragmaPattern.FindStringSubmatch(word)
+	if m == nil {
+		return false
+	}
+	if m[1] == "" {
+		return true
+	}
+	for _, id := range strings.Split(m[1], ",") {
+		if id == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// hasPragma is a cheap check that lets Scan skip pragma handling entirely
+// for content that contains none.
+func hasPragma(content string) bool {
+	return strings.Contains(content, "nosecret") || strings.Contains(content, "gitleaks:allow")
+}
@@ -0,0 +1,175 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// Corpus quality floor. The corpus mixes real-looking secrets with the
+// hashes, digests and documentation placeholders that cause false
+// positives. The floors leave room for a miss or a false positive as the
+// corpus grows, rather than pinning today's exact score; never lower them to
+// make a change pass. corpusMinRulePrecision applies to each rule and
+// detector on its own: none may report more false positives than real
+// secrets, however good the total looks.
+const (
+	corpusMinPrecision     = 0.9
+	corpusMinRecall        = 0.9
+	corpusMinRulePrecision = 0.5
+)
+
+func hasFinding(findings []Finding, rule string) bool {
+	for _, f := range findings {
+		if f.Type == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// TestRuleExamples scans every built-in rule's MustMatch and MustNotMatch
+// examples on their own through the full pipeline.
+func TestRuleExamples(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+	for _, r := range builtinRules {
+		r := r
+		t.Run(r.ID, func(t *testing.T) {
+			if len(r.MustMatch) == 0 || len(r.MustNotMatch) == 0 {
+				t.Fatal("rule needs at least one MustMatch and one MustNotMatch example")
+			}
+			for _, in := range r.MustMatch {
+				if !hasFinding(Scan(in), r.ID) {
+					t.Errorf("expected a finding in %q", in)
+				}
+			}
+			for _, in := range r.MustNotMatch {
+				if hasFinding(Scan(in), r.ID) {
+					t.Errorf("unexpected finding in %q", in)
+				}
+			}
+		})
+	}
+}
+
+// corpusExpectation is one true secret in a corpus file.
+type corpusExpectation struct {
+	Rule string `json:"rule"`
+	Line int    `json:"line"`
+}
+
+// corpusScore counts findings per rule against the corpus expectations.
+type corpusScore struct{ tp, fp, fn int }
+
+func (s corpusScore) precision() float64 {
+	if s.tp+s.fp == 0 {
+		return 1
+	}
+	return float64(s.tp) / float64(s.tp+s.fp)
+}
+
+func (s corpusScore) recall() float64 {
+	if s.tp+s.fn == 0 {
+		return 1
+	}
+	return float64(s.tp) / float64(s.tp+s.fn)
+}
+
+// TestCorpus scans testdata/corpus and reports precision and recall per
+// rule against testdata/corpus/expected.json. Run with -v to see the
+// report.
+func TestCorpus(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+	data, err := os.ReadFile("testdata/corpus/expected.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var expected map[string][]corpusExpectation
+	if err := json.Unmarshal(data, &expected); err != nil {
+		t.Fatal(err)
+	}
+	files, err := filepath.Glob("testdata/corpus/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scores := map[string]*corpusScore{}
+	score := func(rule string) *corpusScore {
+		if scores[rule] == nil {
+			scores[rule] = &corpusScore{}
+		}
+		return scores[rule]
+	}
+	var misses []string
+	for _, path := range files {
+		name := filepath.Base(path)
+		if name == "expected.json" {
+			continue
+		}
+		want, ok := expected[name]
+		if !ok {
+			t.Errorf("%s has no entry in expected.json", name)
+			continue
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		found := map[corpusExpectation]bool{}
+		for _, f := range ScanPath(name, string(content)) {
+			found[corpusExpectation{f.Type, f.Line}] = true
+		}
+		for _, e := range want {
+			if found[e] {
+				score(e.Rule).tp++
+				delete(found, e)
+				continue
+			}
+			score(e.Rule).fn++
+			misses = append(misses, fmt.Sprintf("missed %s:%d %s", name, e.Line, e.Rule))
+		}
+		for e := range found {
+			score(e.Rule).fp++
+			misses = append(misses, fmt.Sprintf("false positive %s:%d %s", name, e.Line, e.Rule))
+		}
+	}
+
+	rules := make([]string, 0, len(scores))
+	var total corpusScore
+	for r, s := range scores {
+		rules = append(rules, r)
+		total.tp += s.tp
+		total.fp += s.fp
+		total.fn += s.fn
+	}
+	sort.Strings(rules)
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n%-24s %4s %4s %4s %9s %7s\n", "rule", "tp", "fp", "fn", "precision", "recall")
+	for _, r := range rules {
+		s := scores[r]
+		fmt.Fprintf(&b, "%-24s %4d %4d %4d %9.2f %7.2f\n", r, s.tp, s.fp, s.fn, s.precision(), s.recall())
+	}
+	fmt.Fprintf(&b, "%-24s %4d %4d %4d %9.2f %7.2f\n", "total", total.tp, total.fp, total.fn, total.precision(), total.recall())
+	sort.Strings(misses)
+	t.Log(b.String() + strings.Join(misses, "\n"))
+
+	for _, r := range rules {
+		if p := scores[r].precision(); p < corpusMinRulePrecision {
+			t.Errorf("%s corpus precision %.2f is below %.2f", r, p, corpusMinRulePrecision)
+		}
+	}
+	if p := total.precision(); p < corpusMinPrecision {
+		t.Errorf("corpus precision %.2f is below %.2f", p, corpusMinPrecision)
+	}
+	if r := total.recall(); r < corpusMinRecall {
+		t.Errorf("corpus recall %.2f is below %.2f", r, corpusMinRecall)
+	}
+}
@@ -1,45 +1,350 @@
 package scanner
 
 import (
+	"context"
+	"errors"
 	"log"
-	"regexp"
+	"math"
+	"time"
 )
 
 type Finding struct {
-	Type     string `json:"type"`
-	Value    string `json:"value"`
-	Severity string `json:"severity"`
+	Type       string  `json:"type"`
+	Value      string  `json:"value"`
+	Severity   string  `json:"severity"`
+	Confidence float64 `json:"confidence"`
+	Line       int     `json:"line"`
+	Column     int     `json:"column"`
+	Start      int     `json:"start"`
+	End        int     `json:"end"`
+	Snippet    string  `json:"snippet"`
+	KeyType    string  `json:"keyType,omitempty"`
+	KeyBits    int     `json:"keyBits,omitempty"`
+	// VerifiedFormat is set when the rule's validator confirmed the value
+	// has the structure or checksum of a real secret.
+	VerifiedFormat bool `json:"verifiedFormat"`
+	// Encoding lists the encodings (base64, url, hex) that had to be
+	// decoded to reveal the secret, outermost first.
+	Encoding []string `json:"encoding,omitempty"`
+	// KeyPath is the config key the value was stored under, e.g.
+	// db.primary.password, for findings from structured files.
+	KeyPath string `json:"keyPath,omitempty"`
+	// Cell and CellPart ("source" or "output") place findings in Jupyter
+	// notebooks; Start, End, Line and Column are then relative to that cell
+	// part rather than the notebook JSON.
+	Cell     *int   `json:"cell,omitempty"`
+	CellPart string `json:"cellPart,omitempty"`
+	// LogField is the JSON or logfmt field of a log line holding the secret.
+	LogField string `json:"logField,omitempty"`
+	// decodedSpans locate a decoded finding inside each decoding layer,
+	// outermost first; Start/End only cover the outer encoded segment.
+	decodedSpans []span
 }
 
-var regexPatterns = map[string]*regexp.Regexp{
-	"AWSAccessKey":    regexp.MustCompile(`AKIA[0-9A-Z]{16}`),
-	"AWSSecretKey":    regexp.MustCompile(`(?i)aws_secret_access_key\s*[:=]\s*['\"]?[A-Za-z0-9/+=]{40}['\"]?`),
-	"JWT":             regexp.MustCompile(`eyJ[a-zA-Z0-9_-]+\.[a-zA-Z0-9._-]+\.[a-zA-Z0-9._-]+`),
-	"GenericAPIKey":   regexp.MustCompile(`(?i)(api|token|secret)[_-]?key[=:]\s*['\"]?[a-zA-Z0-9-_]{16,}['\"]?`),
-	"GithubToken":     regexp.MustCompile(`ghp_[A-Za-z0-9]{36}`),
-	"SlackBotToken":   regexp.MustCompile(`xoxb-[0-9]{11}-[0-9]{11,}-[A-Za-z0-9]{24,}`),
-	"SlackUserToken":  regexp.MustCompile(`xoxp-[0-9]{11}-[0-9]{11,}-[0-9]{11,}-[A-Za-z0-9]{24,}`),
-	"GoogleAPIKey":    regexp.MustCompile(`AIza[0-9A-Za-z\-_]{35}`),
-	"StripeSecretKey": regexp.MustCompile(`sk_live_[0-9a-zA-Z]{24}`),
+// Report is the outcome of scanning one piece of content.
+type Report struct {
+	Findings []Finding
+	// Allowlisted counts matches skipped because of an inline nosecret or
+	// gitleaks:allow pragma.
+	Allowlisted int
+	// Suppressed lists findings dropped in favour of a higher-priority
+	// finding on overlapping bytes.
+	Suppressed []Suppressed
+	// Disabled counts findings dropped by ApplyOverrides.
+	Disabled int
+	// Kind is how Sniff classified the content and KindReason why, when it
+	// isn't ContentText. Binary content has no findings.
+	Kind       string
+	KindReason string
 }
 
+// Scan runs every active rule plus the entropy detector over content.
 func Scan(content string) []Finding {
+	return ScanPath("", content)
+}
+
+// ScanPath is Scan for content that came from a file at path, so rules and
+// allowlists with path filters can apply.
+func ScanPath(path string, content string) []Finding {
+	return ScanReport(path, content).Findings
+}
+
+// ScanReport is ScanPath with scan statistics alongside the findings. A scan
+// that runs past ScanBudget yields an empty report; callers that act on
+// missing findings must use ScanReportContext and check its error.
+func ScanReport(path string, content string) Report {
+	report, _ := ScanReportContext(context.Background(), path, content)
+	return report
+}
+
+// ScanBudget caps the time a single ScanReportContext call may take, on top
+// of any deadline ctx already has. The budget is checked between rules, so
+// one regex already running over the content is allowed to finish. Zero
+// disables it.
+var ScanBudget = 10 * time.Second
+
+// ErrScanBudget is returned when a scan runs past ScanBudget.
+var ErrScanBudget = errors.New("scan exceeded its time budget")
+
+// ScanReportContext is ScanReport with rules evaluated on the worker pool.
+// If ctx is done or ScanBudget runs out before the scan finishes, the
+// partial report is discarded and ctx's error or ErrScanBudget returned.
+func ScanReportContext(ctx context.Context, path string, content string) (Report, error) {
+	report, err := scanBudgeted(ctx, path, content)
+	if err != nil {
+		return Report{Findings: []Finding{}}, err
+	}
+	locate(content, report.Findings)
+	if len(report.Findings) == 0 {
+		log.Printf("scanner: no matches found")
+	}
+	return report, nil
+}
+
+// scanBudgeted is scanContent held to ScanBudget. It returns ErrScanBudget
+// when the budget, rather than ctx, ended the scan.
+func scanBudgeted(ctx context.Context, path string, content string) (Report, error) {
+	scanCtx := ctx
+	if ScanBudget > 0 {
+		var cancel context.CancelFunc
+		scanCtx, cancel = context.WithTimeout(ctx, ScanBudget)
+		defer cancel()
+	}
+	report, err := scanContent(scanCtx, path, content)
+	if err != nil && ctx.Err() == nil {
+		err = ErrScanBudget
+		log.Printf("scanner: %s: %v", path, err)
+	}
+	return report, err
+}
+
+// scanContent returns the findings in content with byte offsets set but no
+// line/column information, except for notebooks whose findings are already
+// located within their cell.
+func scanContent(ctx context.Context, path string, content string) (Report, error) {
+	if nb, ok := parseNotebook(path, content); ok {
+		return scanNotebook(ctx, path, nb)
+	}
+	kind, reason := Sniff(content)
+	if kind == ContentBinary {
+		log.Printf("scanner: %s skipped: %s", path, reason)
+		return Report{Findings: []Finding{}, Kind: kind, KindReason: reason}, nil
+	}
+	if kind == ContentMinified {
+		log.Printf("scanner: %s scanned with reduced rules: %s", path, reason)
+	}
+	report, err := scanText(ctx, path, content, kind == ContentMinified)
+	if err != nil {
+		return report, err
+	}
+	report.Kind, report.KindReason = kind, reason
+	if isLog(path, content) {
+		annotateLogFields(content, report.Findings)
+	}
+	return report, nil
+}
+
+// scanText scans plain text content: rules, entropy, decoding, structured
+// key/values and inline pragmas. Minified content skips the catch-all
+// rules, the entropy detector and decoding.
+func scanText(ctx context.Context, path string, content string, minified bool) (Report, error) {
+	depth := DecodeDepth
+	if minified {
+		depth = 0
+	}
+	findings, err := scanLayer(ctx, path, content, depth, minified)
+	if err != nil {
+		return Report{}, err
+	}
+	// a value already caught by a rule just gains its key path
+	for _, f := range scanStructured(path, content) {
+		if i := overlappingFinding(findings, f); i >= 0 {
+			findings[i].KeyPath = f.KeyPath
+			continue
+		}
+		findings = append(findings, f)
+	}
+	report := Report{Findings: findings}
+	if hasPragma(content) {
+		kept := findings[:0]
+		for _, f := range findings {
+			if pragmaAllows(content, f.Start, f.Type) {
+				report.Allowlisted++
+				continue
+			}
+			kept = append(kept, f)
+		}
+		report.Findings = kept
+	}
+	report.Findings, report.Suppressed = resolveOverlaps(report.Findings)
+	return report, nil
+}
+
+// scanLayer runs the rules and the entropy detector over content, then
+// looks inside encoded segments up to depth layers down. For large inputs
+// each rule, the decoder and the entropy detector run as separate tasks on
+// the worker pool; results are merged in a fixed order so the output is the
+// same as a sequential scan. The reduced scan of minified content leaves
+// out catch-all rules and the entropy detector.
+func scanLayer(ctx context.Context, path string, content string, depth int, reduced bool) ([]Finding, error) {
+	rules := active.candidates(content)
+	if reduced {
+		rules = reducedRules(rules)
+	}
+	perRule := make([][]Finding, len(rules))
+	perClaim := make([][]span, len(rules))
+	var decoded, entropyFindings []Finding
+	var revealing []span
+	var decodeErr error
+	task := func(i int) {
+		switch i {
+		case len(rules):
+			decoded, revealing, decodeErr = scanDecoded(ctx, path, content, depth)
+		case len(rules) + 1:
+			if !reduced {
+				entropyFindings = scanEntropy(content)
+			}
+		default:
+			perRule[i], perClaim[i] = matchRule(rules[i], path, content)
+		}
+	}
+	if len(content) < parallelMinSize {
+		for i := 0; i < len(rules)+2; i++ {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			task(i)
+		}
+	} else if err := Parallel(ctx, len(rules)+2, task); err != nil {
+		return nil, err
+	}
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
 	findings := []Finding{}
-	for name, re := range regexPatterns {
-		matches := re.FindAllString(content, -1)
-		if len(matches) > 0 {
-			log.Printf("scanner: pattern=%s matches=%d sample=%q", name, len(matches), matches[0])
+	claimed := []span{}
+	for i := range rules {
+		findings = append(findings, perRule[i]...)
+		claimed = append(claimed, perClaim[i]...)
+	}
+	// a token already matched by a rule, or an encoded blob that decodes to
+	// a known secret, is reported as that secret, not as a high-entropy
+	// string
+	claimed = append(claimed, revealing...)
+	n := 0
+	for _, f := range entropyFindings {
+		if !overlapsAny(claimed, f.Start, f.End) {
+			findings = append(findings, f)
+			n++
 		}
-		for _, match := range matches {
-			findings = append(findings, Finding{
-				Type:     name,
-				Value:    match,
-				Severity: "high",
-			})
+	}
+	if n > 0 {
+		log.Printf("scanner: entropy matches=%d", n)
+	}
+	for _, f := range decoded {
+		if !duplicateOf(findings, f) {
+			findings = append(findings, f)
 		}
 	}
-	if len(findings) == 0 {
-		log.Printf("scanner: no matches found")
+	return findings, nil
+}
+
+// matchRule returns the findings of a single rule over content and the full
+// match spans they claim from the entropy detector.
+func matchRule(rule *Rule, path string, content string) ([]Finding, []span) {
+	if !rule.appliesTo(path) {
+		return nil, nil
+	}
+	matches := rule.Regex.FindAllStringSubmatchIndex(content, -1)
+	if len(matches) > 0 {
+		log.Printf("scanner: pattern=%s matches=%d sample=%q", rule.ID, len(matches), content[matches[0][0]:matches[0][1]])
+	}
+	var findings []Finding
+	var claimed []span
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		g := rule.SecretGroup
+		if g == 0 && rule.FirstGroup {
+			for i := 1; 2*i+1 < len(m); i++ {
+				if m[2*i] >= 0 && m[2*i+1] > m[2*i] {
+					g = i
+					break
+				}
+			}
+		}
+		if g > 0 && 2*g+1 < len(m) && m[2*g] >= 0 {
+			start, end = m[2*g], m[2*g+1]
+		}
+		secret := content[start:end]
+		if rule.Entropy > 0 && shannonEntropy(secret) < rule.Entropy {
+			continue
+		}
+		if rule.allowed(candidate{path: path, match: content[m[0]:m[1]], secret: secret, line: lineAt(content, m[0], m[1])}) {
+			continue
+		}
+		f := Finding{
+			Type:       rule.ID,
+			Value:      secret,
+			Severity:   rule.Severity,
+			Confidence: rule.confidence(),
+			Start:      start,
+			End:        end,
+		}
+		if rule.Validate != nil {
+			switch {
+			case rule.Validate(secret, &f):
+				f.VerifiedFormat = true
+			case rule.DowngradeInvalid:
+				f.Severity = downgrade(f.Severity)
+				f.Confidence = math.Round(f.Confidence*50) / 100
+			default:
+				continue
+			}
+		}
+		if reason := placeholderReason(f.Value, rule.DisabledFilters); reason != "" {
+			log.Printf("scanner: pattern=%s match suppressed by %s filter", rule.ID, reason)
+			continue
+		}
+		findings = append(findings, f)
+		claimed = append(claimed, span{m[0], m[1]})
+	}
+	return findings, claimed
+}
+
+// overlappingFinding returns the index of the first finding sharing bytes
+// with f, or -1.
+func overlappingFinding(findings []Finding, f Finding) int {
+	for i, g := range findings {
+		if findingsOverlap(g, f) {
+			return i
+		}
+	}
+	return -1
+}
+
+// findingsOverlap reports whether a and b share bytes. Findings decoded
+// from the same encoded segment all carry its offsets, so they are compared
+// by where they sit in the decoded text instead.
+func findingsOverlap(a, b Finding) bool {
+	sa, sb := span{a.Start, a.End}, span{b.Start, b.End}
+	for i := 0; ; i++ {
+		if sa.start >= sb.end || sb.start >= sa.end {
+			return false
+		}
+		if sa != sb || i >= len(a.decodedSpans) || i >= len(b.decodedSpans) {
+			return true
+		}
+		sa, sb = a.decodedSpans[i], b.decodedSpans[i]
+	}
+}
+
+// duplicateOf reports whether f, found by decoding, is already covered by a
+// plain-text finding with the same rule and value at the same place.
+func duplicateOf(findings []Finding, f Finding) bool {
+	for _, g := range findings {
+		if g.Type == f.Type && g.Value == f.Value && g.Start < f.End && f.Start < g.End {
+			return true
+		}
 	}
-	return findings
+	return false
 }
@@ -0,0 +1,85 @@
+package scanner
+
+import (
+	"context"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp/syntax"
+	"testing"
+)
+
+// FuzzScan feeds arbitrary content through the whole pipeline and checks
+// the invariants callers rely on: no panics, offsets inside the content,
+// 1-based locations and identical results on a second run.
+func FuzzScan(f *testing.F) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+	for _, r := range builtinRules {
+		for _, ex := range append(r.MustMatch, r.MustNotMatch...) {
+			f.Add("", ex)
+		}
+	}
+	files, _ := filepath.Glob("testdata/corpus/*")
+	for _, path := range files {
+		if data, err := os.ReadFile(path); err == nil {
+			f.Add(filepath.Base(path), string(data))
+		}
+	}
+	f.Add("x.ipynb", `{"nbformat":4,"cells":[{"source":"a","outputs":[{"text":["b"]}]}]}`)
+	f.Add("app.log", "level=info token=\"a\\\"b\"\n{\"a\":[{\"b\":\"c\"}]}\n")
+
+	f.Fuzz(func(t *testing.T, path string, content string) {
+		report, err := ScanReportContext(context.Background(), path, content)
+		if err != nil {
+			t.Skip(err)
+		}
+		for _, fd := range report.Findings {
+			if fd.Start < 0 || fd.Start > fd.End || (fd.Cell == nil && fd.End > len(content)) {
+				t.Fatalf("%s: offsets %d-%d outside content of %d bytes", fd.Type, fd.Start, fd.End, len(content))
+			}
+			if fd.Line < 1 || fd.Column < 1 {
+				t.Fatalf("%s: location %d:%d is not 1-based", fd.Type, fd.Line, fd.Column)
+			}
+		}
+		again := ScanPath(path, content)
+		if !reflect.DeepEqual(report.Findings, again) {
+			t.Fatalf("second scan differs:\n%+v\n%+v", report.Findings, again)
+		}
+	})
+}
+
+// FuzzRulesFile checks that the rules file loader never panics and that
+// every rule it accepts has a compiled regex free of the constructs the
+// lint rejects.
+func FuzzRulesFile(f *testing.F) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+	f.Add([]byte("[[rules]]\nid = \"InternalToken\"\nregex = '''itk_[a-z0-9]{32}'''\nseverity = \"high\"\nkeywords = [\"itk_\"]\n"), ".toml")
+	f.Add([]byte("rules:\n  - id: InternalToken\n    regex: 'itk_[a-z0-9]{32}'\n    mustMatch: [itk_9f2kq8z3lp0vn7rt2yb5wm1xc4hd6gja]\n"), ".yaml")
+	f.Add([]byte("rules:\n  - id: Bad\n    regex: '(a+)+'\n"), ".yml")
+	f.Add([]byte("[[rules]]\nid = \"x\"\nregex = \"[\"\n"), ".toml")
+
+	f.Fuzz(func(t *testing.T, data []byte, ext string) {
+		rules, err := parseRulesFile(data, ext)
+		if err != nil {
+			return
+		}
+		for _, r := range rules {
+			if r.Regex == nil {
+				t.Fatalf("%s: accepted without a regex", r.ID)
+			}
+			// only the structural checks: the match cost is deterministic
+			// but too slow to repeat on every fuzz input
+			tree, err := syntax.Parse(r.Regex.String(), syntax.Perl)
+			if err != nil {
+				t.Fatalf("%s: accepted regex does not parse: %v", r.ID, err)
+			}
+			if err := lintTree(tree); err != nil {
+				t.Fatalf("%s: accepted a regex the lint rejects: %v", r.ID, err)
+			}
+		}
+	})
+}
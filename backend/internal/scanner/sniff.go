@@ -0,0 +1,82 @@
+package scanner
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Content kinds reported by Sniff.
+const (
+	ContentText     = "text"
+	ContentBinary   = "binary"
+	ContentMinified = "minified"
+)
+
+// MinifiedLineLength is the average line length above which content of at
+// least minifiedMinSize bytes is treated as minified.
+var MinifiedLineLength = 500
+
+const (
+	// sniffSize is how much of the content the binary check looks at.
+	sniffSize = 8 << 10
+	// binaryPercent is the share of invalid UTF-8 and control bytes in
+	// the sniffed prefix above which content is binary.
+	binaryPercent   = 10
+	minifiedMinSize = 4 << 10
+)
+
+// Sniff classifies content as text, binary or minified and says why when it
+// isn't plain text. Content is binary when its first 8 KiB hold a NUL byte
+// (the heuristic git uses) or are more than 10% invalid UTF-8 and control
+// characters. Binary content is not scanned. Minified content, one very
+// long line or a few, is scanned with a reduced rule set.
+func Sniff(content string) (kind, reason string) {
+	head := content
+	truncated := len(head) > sniffSize
+	if truncated {
+		head = head[:sniffSize]
+	}
+	if strings.IndexByte(head, 0) >= 0 {
+		return ContentBinary, "binary: NUL byte"
+	}
+	invalid, control := 0, 0
+	for i := 0; i < len(head); {
+		r, size := utf8.DecodeRuneInString(head[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			// the cut at sniffSize may split the last rune
+			if !truncated || len(head)-i >= utf8.UTFMax {
+				invalid++
+			}
+		case r < 0x20 && !strings.ContainsRune("\t\n\v\f\r", r), r == 0x7f:
+			control++
+		}
+		i += size
+	}
+	if (invalid+control)*100 > len(head)*binaryPercent {
+		if invalid >= control {
+			return ContentBinary, "binary: invalid UTF-8"
+		}
+		return ContentBinary, "binary: control characters"
+	}
+	if len(content) >= minifiedMinSize {
+		lines := strings.Count(content, "\n") + 1
+		if avg := len(content) / lines; avg > MinifiedLineLength {
+			return ContentMinified, fmt.Sprintf("minified: average line length %d", avg)
+		}
+	}
+	return ContentText, ""
+}
+
+// reducedRules drops the catch-all rules, whose loose patterns mostly match
+// noise in minified code, and keeps the provider-specific ones.
+func reducedRules(rules []*Rule) []*Rule {
+	out := make([]*Rule, 0, len(rules))
+	for _, r := range rules {
+		if r.Priority >= 0 {
+			out = append(out, r)
+		}
+	}
+	return out
+}
@@ -0,0 +1,102 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"unicode/utf8"
+)
+
+// StreamChunkSize is how much new input ScanReader reads per window. Each
+// window also repeats the last 2*StreamOverlap bytes of the previous one,
+// rounded back to a rune boundary: a secret straddling a chunk boundary is
+// still seen whole, and a match at the start of the window still has its
+// left-hand context. StreamOverlap must be larger than the longest secret a
+// rule can match.
+var (
+	StreamChunkSize = 1 << 20
+	StreamOverlap   = 16 << 10
+)
+
+// ScanReader scans r in overlapping windows, calling emit for each finding
+// as soon as its window has been scanned. Memory use is bounded by
+// StreamChunkSize+StreamOverlap regardless of input size. Offsets, lines
+// and columns are relative to the start of the whole stream. A non-nil
+// error from emit stops the scan and is returned.
+func ScanReader(path string, r io.Reader, emit func(Finding) error) error {
+	return ScanReaderContext(context.Background(), path, r, emit)
+}
+
+// ScanReaderContext is ScanReader that stops with ctx's error once ctx is
+// done. Each window is held to ScanBudget and one that runs past it stops
+// the scan with ErrScanBudget. Either way, findings from windows already
+// scanned have been emitted.
+func ScanReaderContext(ctx context.Context, path string, r io.Reader, emit func(Finding) error) error {
+	overlap := StreamOverlap
+	chunk := StreamChunkSize
+	if chunk <= overlap {
+		chunk = overlap + 1
+	}
+	buf := make([]byte, 0, chunk+2*overlap)
+	// offset, line and col describe where buf[0] sits in the stream; line
+	// and col count completed lines and runes before it on its line. The
+	// first skip bytes of buf were already owned by the previous window.
+	offset, line, col, skip := 0, 0, 0, 0
+	for {
+		n, err := io.ReadFull(r, buf[len(buf):cap(buf)])
+		buf = buf[:len(buf)+n]
+		final := errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+		if err != nil && !final {
+			return err
+		}
+
+		// Findings starting in the last overlap bytes belong to the next
+		// window, which sees them with full right-hand context.
+		owned := len(buf) - overlap
+		if final {
+			owned = len(buf)
+		}
+		content := string(buf)
+		report, err := scanBudgeted(ctx, path, content)
+		if err != nil {
+			return err
+		}
+		findings := report.Findings
+		locate(content, findings)
+		for _, f := range findings {
+			if f.Start < skip || f.Start >= owned {
+				continue
+			}
+			if f.Line == 1 {
+				f.Column += col
+			}
+			f.Line += line
+			f.Start += offset
+			f.End += offset
+			if err := emit(f); err != nil {
+				return err
+			}
+		}
+		if final {
+			return nil
+		}
+
+		// carry whole runes, so the next window's columns count the one
+		// split here just once
+		carry := len(buf) - 2*overlap
+		for carry > 0 && !utf8.RuneStart(buf[carry]) {
+			carry--
+		}
+		done := buf[:carry]
+		if nl := bytes.LastIndexByte(done, '\n'); nl >= 0 {
+			line += bytes.Count(done, []byte{'\n'})
+			col = utf8.RuneCount(done[nl+1:])
+		} else {
+			col += utf8.RuneCount(done)
+		}
+		skip = owned - carry
+		offset += carry
+		buf = buf[:copy(buf, buf[carry:])]
+	}
+}
@@ -0,0 +1,345 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Structured-format scanning: config files are parsed and values stored
+// under sensitive key names are reported even when the value itself has no
+// recognisable shape (password: hunter2).
+
+// sensitiveKey matches key names that hold a secret. It is anchored at the
+// end, so keys that name something about a secret rather than holding one
+// (password_file, token_ttl, secret_name) don't match.
+var sensitiveKey = regexp.MustCompile(`(?i)(pass(word|wd|phrase)?|pwd|secret|token|api[_-]?key|access[_-]?key|private[_-]?key|client[_-]?secret|credentials?|auth[_-]?key|signing[_-]?key|encryption[_-]?key)$`)
+
+type keyValue struct {
+	path  string // dotted key path, e.g. db.primary.password
+	key   string // last path segment
+	value string
+	// start and end are the byte offsets of the value's raw text in content,
+	// which differ from len(value) when the value was escaped. start is -1
+	// when the parser can't tell and it has to be searched for.
+	start, end int
+}
+
+// structuredFormat picks a parser from the file name, falling back to JSON
+// for unnamed content that looks like a JSON document.
+func structuredFormat(path string, content string) string {
+	base := strings.ToLower(filepath.Base(path))
+	switch ext := filepath.Ext(base); {
+	case ext == ".json":
+		return "json"
+	case ext == ".yaml" || ext == ".yml":
+		return "yaml"
+	case ext == ".toml":
+		return "toml"
+	case ext == ".properties":
+		return "properties"
+	case base == ".env" || strings.HasPrefix(base, ".env.") || ext == ".env":
+		return "dotenv"
+	}
+	if path == "" {
+		if t := strings.TrimSpace(content); strings.HasPrefix(t, "{") && json.Valid([]byte(t)) {
+			return "json"
+		}
+	}
+	return ""
+}
+
+// scanStructured reports string values stored under sensitive keys in JSON,
+// YAML, TOML, dotenv and .properties content. Parse errors just mean no
+// structured findings; the regex rules still ran over the raw text.
+func scanStructured(path string, content string) []Finding {
+	var kvs []keyValue
+	switch structuredFormat(path, content) {
+	case "json":
+		kvs = jsonKeyValues(content)
+	case "toml":
+		var doc map[string]any
+		if toml.Unmarshal([]byte(content), &doc) == nil {
+			walkValue("", doc, &kvs)
+		}
+	case "yaml":
+		kvs = yamlKeyValues(content)
+	case "dotenv":
+		kvs = lineKeyValues(content, "=", "#")
+	case "properties":
+		kvs = lineKeyValues(content, "=:", "#!")
+	default:
+		return nil
+	}
+
+	findings := []Finding{}
+	for _, kv := range kvs {
+		if !sensitiveKey.MatchString(kv.key) || len(kv.value) < 6 {
+			continue
+		}
+		if placeholderReason(kv.value, nil) != "" || isPlaceholderCredential("", kv.value) {
+			continue
+		}
+		start, end := kv.start, kv.end
+		if start < 0 {
+			start, end = locateValue(content, kv.path, kv.value)
+		}
+		if start < 0 {
+			continue
+		}
+		findings = append(findings, Finding{
+			Type:       "SensitiveKeyValue",
+			Value:      kv.value,
+			Severity:   detectorSeverities["SensitiveKeyValue"],
+			Confidence: 0.5,
+			Start:      start,
+			End:        end,
+			KeyPath:    kv.path,
+		})
+	}
+	return findings
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// jsonKeyValues reads the string values of a JSON document token by token,
+// so each is placed by where the decoder found it, escaped or not. A syntax
+// error anywhere yields nothing, as a failed decode would.
+func jsonKeyValues(content string) []keyValue {
+	dec := json.NewDecoder(strings.NewReader(content))
+	dec.UseNumber()
+	out := []keyValue{}
+	if walkJSON(dec, content, "", "", &out) != nil {
+		return nil
+	}
+	return out
+}
+
+// walkJSON reads the next value from dec. Strings are recorded when they
+// are object members; key is "" for array elements and the top level.
+func walkJSON(dec *json.Decoder, content, path, key string, out *[]keyValue) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		for i := 0; dec.More(); i++ {
+			if t == '[' {
+				err = walkJSON(dec, content, fmt.Sprintf("%s[%d]", path, i), "", out)
+			} else if k, kerr := dec.Token(); kerr != nil {
+				err = kerr
+			} else {
+				err = walkJSON(dec, content, joinKey(path, k.(string)), k.(string), out)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		// the closing delimiter
+		_, err = dec.Token()
+		return err
+	case string:
+		if key != "" {
+			// the decoder has just read the closing quote
+			end := int(dec.InputOffset()) - 1
+			*out = append(*out, keyValue{path: path, key: key, value: t, start: openingQuote(content, end) + 1, end: end})
+		}
+	}
+	return nil
+}
+
+// openingQuote returns the offset of the quote opening the JSON string
+// whose closing quote is at end.
+func openingQuote(content string, end int) int {
+	for i := end - 1; i >= 0; i-- {
+		if content[i] != '"' {
+			continue
+		}
+		n := 0
+		for j := i - 1; j >= 0 && content[j] == '\\'; j-- {
+			n++
+		}
+		if n%2 == 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// walkValue flattens decoded TOML into key paths. Map keys are visited
+// in sorted order so output is stable.
+func walkValue(prefix string, v any, out *[]keyValue) {
+	switch t := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if s, ok := t[k].(string); ok {
+				*out = append(*out, keyValue{path: joinKey(prefix, k), key: k, value: s, start: -1})
+				continue
+			}
+			walkValue(joinKey(prefix, k), t[k], out)
+		}
+	case []any:
+		for i, e := range t {
+			walkValue(fmt.Sprintf("%s[%d]", prefix, i), e, out)
+		}
+	case []map[string]any:
+		for i, e := range t {
+			walkValue(fmt.Sprintf("%s[%d]", prefix, i), e, out)
+		}
+	}
+}
+
+// quotedAssignment matches the rest of a `key = "value"` line after the key
+// name, capturing the raw text of a single-line basic or literal string.
+var quotedAssignment = regexp.MustCompile(`^["']?[ \t]*[=:][ \t]*(?:"((?:[^"\\\n]|\\.)*)"|'([^'\n]*)')`)
+
+// locateValue finds the raw text of value in content by walking the key
+// path segments in order, so db.replica.password isn't confused with
+// db.primary.password. The quoted string right after the last key is taken
+// as is, escapes included; otherwise value has to appear literally after
+// it. It returns -1, -1 rather than guess when the path isn't found.
+func locateValue(content, path, value string) (int, int) {
+	from := 0
+	for _, seg := range strings.Split(path, ".") {
+		if i := strings.IndexByte(seg, '['); i >= 0 {
+			seg = seg[:i]
+		}
+		i := strings.Index(content[from:], seg)
+		if i < 0 {
+			return -1, -1
+		}
+		from += i + len(seg)
+	}
+	if m := quotedAssignment.FindStringSubmatchIndex(content[from:]); m != nil {
+		g := 1
+		if m[2] < 0 {
+			g = 2
+		}
+		return from + m[2*g], from + m[2*g+1]
+	}
+	if i := strings.Index(content[from:], value); i >= 0 {
+		return from + i, from + i + len(value)
+	}
+	return -1, -1
+}
+
+func yamlKeyValues(content string) []keyValue {
+	lineStarts := []int{0}
+	for i := 0; i < len(content); i++ {
+		if content[i] == '\n' {
+			lineStarts = append(lineStarts, i+1)
+		}
+	}
+	out := []keyValue{}
+	dec := yaml.NewDecoder(strings.NewReader(content))
+	for {
+		var doc yaml.Node
+		if err := dec.Decode(&doc); err != nil {
+			// io.EOF or a parse error: either way, stop
+			return out
+		}
+		walkYAML("", &doc, lineStarts, content, &out)
+	}
+}
+
+func walkYAML(prefix string, n *yaml.Node, lineStarts []int, content string, out *[]keyValue) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		for _, c := range n.Content {
+			walkYAML(prefix, c, lineStarts, content, out)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, v := n.Content[i], n.Content[i+1]
+			if v.Kind == yaml.ScalarNode && v.Tag == "!!str" {
+				start := -1
+				if v.Line-1 < len(lineStarts) {
+					// Column points at the opening quote for quoted scalars
+					off := lineStarts[v.Line-1] + v.Column - 1
+					if j := strings.Index(content[off:], v.Value); j >= 0 && j <= 1 {
+						start = off + j
+					}
+				}
+				*out = append(*out, keyValue{path: joinKey(prefix, k.Value), key: k.Value, value: v.Value, start: start, end: start + len(v.Value)})
+				continue
+			}
+			walkYAML(joinKey(prefix, k.Value), v, lineStarts, content, out)
+		}
+	case yaml.SequenceNode:
+		for i, c := range n.Content {
+			walkYAML(fmt.Sprintf("%s[%d]", prefix, i), c, lineStarts, content, out)
+		}
+	}
+}
+
+// lineKeyValues parses dotenv and .properties style "key<sep>value" lines.
+func lineKeyValues(content, seps, comments string) []keyValue {
+	out := []keyValue{}
+	sc := bufio.NewScanner(strings.NewReader(content))
+	sc.Buffer(make([]byte, 0, 64*1024), len(content)+1)
+	sc.Split(scanLinesKeepOffsets)
+	offset := 0
+	for sc.Scan() {
+		raw := sc.Text()
+		lineStart := offset
+		offset += len(raw)
+		line := strings.TrimRight(raw, "\r\n")
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" || strings.ContainsRune(comments, rune(trimmed[0])) {
+			continue
+		}
+		i := strings.IndexAny(trimmed, seps)
+		if i <= 0 {
+			continue
+		}
+		key := strings.TrimSpace(strings.TrimPrefix(trimmed[:i], "export "))
+		rest := trimmed[i+1:]
+		valStart := lineStart + (len(line) - len(trimmed)) + i + 1
+		lead := len(rest) - len(strings.TrimLeft(rest, " \t"))
+		rest, valStart = rest[lead:], valStart+lead
+		if j := strings.Index(rest, " #"); j >= 0 && seps == "=" {
+			rest = rest[:j]
+		}
+		rest = strings.TrimRight(rest, " \t")
+		if len(rest) >= 2 && (rest[0] == '"' || rest[0] == '\'') && rest[len(rest)-1] == rest[0] {
+			rest, valStart = rest[1:len(rest)-1], valStart+1
+		}
+		out = append(out, keyValue{path: key, key: key, value: rest, start: valStart, end: valStart + len(rest)})
+	}
+	return out
+}
+
+// scanLinesKeepOffsets is bufio.ScanLines without dropping the line ending,
+// so callers can keep byte offsets exact.
+func scanLinesKeepOffsets(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, io.EOF
+	}
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		return i + 1, data[:i+1], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
@@ -0,0 +1,150 @@
+package scanner
+
+import "testing"
+
+func TestScanStructured(t *testing.T) {
+	// raw is the text the finding's offsets cover, when escaping makes it
+	// differ from value
+	type want struct{ keyPath, value, raw string }
+	cases := []struct {
+		name    string
+		path    string
+		content string
+		want    []want
+	}{
+		{
+			name: "json",
+			path: "config.json",
+			content: `{"db": {"primary": {"host": "db1", "password": "hunter2hunter2"},
+ "replica": {"password": "tr0ub4dor&3"}},
+ "password_file": "/run/secrets/db", "workers": [{"token": "w0rk3r-t0k3n"}]}`,
+			want: []want{
+				{"db.primary.password", "hunter2hunter2", ""},
+				{"db.replica.password", "tr0ub4dor&3", ""},
+				{"workers[0].token", "w0rk3r-t0k3n", ""},
+			},
+		},
+		{
+			name:    "unnamed json",
+			content: `{"client_secret": "s3cr3t-v4lue", "name": "billing"}`,
+			want:    []want{{"client_secret", "s3cr3t-v4lue", ""}},
+		},
+		{
+			name: "yaml",
+			path: "values.yaml",
+			content: `db:
+  primary:
+    password: "hunter2hunter2"
+  password_secret_name: db-credentials
+smtp:
+  - user: mailer
+    passphrase: 'tr0ub4dor&3'
+---
+api_key: k3y-in-2nd-doc
+`,
+			want: []want{
+				{"db.primary.password", "hunter2hunter2", ""},
+				{"smtp[0].passphrase", "tr0ub4dor&3", ""},
+				{"api_key", "k3y-in-2nd-doc", ""},
+			},
+		},
+		{
+			name: "toml",
+			path: "app.toml",
+			content: `[database]
+user = "billing"
+password = "hunter2hunter2"
+token_ttl = "30m"
+`,
+			want: []want{{"database.password", "hunter2hunter2", ""}},
+		},
+		{
+			name: "dotenv",
+			path: ".env.production",
+			content: `# comment
+export DB_PASSWORD="hunter2hunter2"
+SESSION_SECRET=tr0ub4dor&3 # rotated monthly
+DB_PASSWORD_FILE=/run/secrets/db
+`,
+			want: []want{
+				{"DB_PASSWORD", "hunter2hunter2", ""},
+				{"SESSION_SECRET", "tr0ub4dor&3", ""},
+			},
+		},
+		{
+			name: "properties",
+			path: "application.properties",
+			content: `! comment
+spring.datasource.password: hunter2hunter2
+app.signing_key = tr0ub4dor&3
+`,
+			want: []want{
+				{"spring.datasource.password", "hunter2hunter2", ""},
+				{"app.signing_key", "tr0ub4dor&3", ""},
+			},
+		},
+		{
+			name:    "escaped json",
+			path:    "config.json",
+			content: `{"note": "hunter2hunter2", "db": {"password": "hunter2hunter2", "token": "tr0ub\"4dor\u00263"}}`,
+			want: []want{
+				{"db.password", "hunter2hunter2", ""},
+				{"db.token", "tr0ub\"4dor&3", `tr0ub\"4dor\u00263`},
+			},
+		},
+		{
+			name: "escaped toml",
+			path: "app.toml",
+			content: `# password = "hunter2hunter2" until the rotation
+[database]
+password = "hunter2\u0068unter2"
+token = 'tr0ub\4dor&3'
+`,
+			want: []want{
+				{"database.password", "hunter2hunter2", `hunter2\u0068unter2`},
+				{"database.token", `tr0ub\4dor&3`, ""},
+			},
+		},
+		{
+			name:    "escaped yaml",
+			path:    "values.yaml",
+			content: "password: \"hunter2\\thunter2\"\n",
+			want:    []want{{"password", "hunter2\thunter2", `hunter2\thunter2`}},
+		},
+		{
+			name:    "placeholders and short values",
+			path:    ".env",
+			content: "PASSWORD=changeme\nTOKEN=abc\nAPI_KEY=${API_KEY}\n",
+		},
+		{
+			name:    "parse error",
+			path:    "broken.json",
+			content: `{"password": "hunter2hunter2"`,
+		},
+		{
+			name:    "unknown format",
+			path:    "notes.txt",
+			content: "password: hunter2hunter2\n",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := scanStructured(tc.path, tc.content)
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %d findings, want %d: %+v", len(got), len(tc.want), got)
+			}
+			for i, f := range got {
+				if f.KeyPath != tc.want[i].keyPath || f.Value != tc.want[i].value {
+					t.Errorf("finding %d = %s=%q, want %s=%q", i, f.KeyPath, f.Value, tc.want[i].keyPath, tc.want[i].value)
+				}
+				raw := tc.want[i].raw
+				if raw == "" {
+					raw = f.Value
+				}
+				if tc.content[f.Start:f.End] != raw {
+					t.Errorf("%s: offsets %d-%d point at %q", f.KeyPath, f.Start, f.End, tc.content[f.Start:f.End])
+				}
+			}
+		})
+	}
+}
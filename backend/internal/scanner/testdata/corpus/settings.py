@@ -0,0 +1,18 @@
+import os
+
+DEBUG = False
+ALLOWED_HOSTS = ["billing.internal"]
+
+# Django signs sessions with this
+SECRET_KEY = "q8%z!v3l@0n#r7t2y$b5k^m1w&x4p(6c"
+
+OPENAI_API_KEY = "sk-proj-kzh6RZxBSrwI0nRjFic99B_w5w4uWTA35CxBrN5bT3BlbkFJIr8JlsL3S_DYzCYxFxwBLqIsTMkQ64wuHBk3AzFZ"
+
+CACHES = {
+    "default": {
+        "BACKEND": "django.core.cache.backends.redis.RedisCache",
+        "LOCATION": os.environ.get("REDIS_URL", "redis://localhost:6379/1"),
+    }
+}
+
+AWS_SECRET_ACCESS_KEY = os.environ["AWS_SECRET_ACCESS_KEY"]
@@ -0,0 +1,91 @@
+package scanner
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"hash/crc32"
+	"strings"
+)
+
+// Offline format validators. Each returns true when the secret has the
+// structure (and checksum, where the format has one) its provider issues.
+// Rules pick what happens to a failing match with DowngradeInvalid.
+
+const base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// base62 encodes n left-padded with zeros to width.
+func base62(n uint32, width int) string {
+	out := make([]byte, width)
+	for i := width - 1; i >= 0; i-- {
+		out[i] = base62Alphabet[n%62]
+		n /= 62
+	}
+	return string(out)
+}
+
+// validateGithubToken checks a ghp_/gho_/ghu_/ghs_/ghr_ token: 30 random
+// base62 characters followed by their CRC32 as 6 base62 digits.
+func validateGithubToken(secret string, f *Finding) bool {
+	if len(secret) != 40 || secret[3] != '_' {
+		return false
+	}
+	body, sum := secret[4:34], secret[34:]
+	return base62(crc32.ChecksumIEEE([]byte(body)), 6) == sum
+}
+
+// validateGithubPAT checks the github_pat_<22>_<59> layout of fine-grained
+// tokens. GitHub doesn't document their checksum, so this is structural only.
+func validateGithubPAT(secret string, f *Finding) bool {
+	parts := strings.Split(strings.TrimPrefix(secret, "github_pat_"), "_")
+	return len(parts) == 2 && len(parts[0]) == 22 && len(parts[1]) == 59
+}
+
+// validateAWSAccessKey checks that the 16 characters after the prefix are
+// base32 (A-Z, 2-7), which every real key ID is.
+func validateAWSAccessKey(secret string, f *Finding) bool {
+	if len(secret) != 20 {
+		return false
+	}
+	for _, c := range secret[4:] {
+		if !(c >= 'A' && c <= 'Z') && !(c >= '2' && c <= '7') {
+			return false
+		}
+	}
+	return true
+}
+
+// validateJWT checks that the header decodes to a JSON object with an alg,
+// and for signed tokens that the payload is JSON too.
+func validateJWT(secret string, f *Finding) bool {
+	parts := strings.Split(secret, ".")
+	if len(parts) != 3 && len(parts) != 5 {
+		return false
+	}
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if !decodeJWTPart(parts[0], &header) || header.Alg == "" {
+		return false
+	}
+	if len(parts) == 3 {
+		var payload map[string]any
+		return decodeJWTPart(parts[1], &payload)
+	}
+	return true
+}
+
+func decodeJWTPart(part string, v any) bool {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(part, "="))
+	return err == nil && json.Unmarshal(data, v) == nil
+}
+
+// downgrade returns the severity one step below sev.
+func downgrade(sev string) string {
+	switch sev {
+	case "critical":
+		return "high"
+	case "high":
+		return "medium"
+	}
+	return "low"
+}
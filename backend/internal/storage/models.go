@@ -5,11 +5,18 @@ import "time"
 type Issue struct {
 	ID          string    `gorm:"primaryKey;type:uuid" json:"id"`
 	Type        string    `json:"type"`
+	Severity    string    `gorm:"size:16;index" json:"severity"`
+	Confidence  float64   `json:"confidence"`
 	Status      string    `json:"status"`
 	Repo        string    `json:"repo"`
 	Commit      string    `json:"commit"`
 	Channel     string    `json:"channel"`
 	File        string    `json:"file"`
+	Line        int       `json:"line"`
+	Column      int       `json:"column"`
+	StartOffset int       `json:"startOffset"`
+	EndOffset   int       `json:"endOffset"`
+	Snippet     string    `gorm:"type:text" json:"snippet"`
 	Content     string    `gorm:"type:text" json:"content"`
 	Fingerprint string    `gorm:"index;size:64" json:"-"`
 	CreatedAt   time.Time `json:"createdAt"`
@@ -25,6 +32,55 @@ type Suppression struct {
 	UpdatedAt   time.Time  `json:"updatedAt"`
 }
 
+// RuleOverride turns a rule off or changes its severity for scans of one
+// repo or one channel; exactly one of Repo and Channel is set.
+type RuleOverride struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	RuleID    string    `gorm:"size:128;uniqueIndex:idx_rule_override_scope" json:"ruleId"`
+	Repo      string    `gorm:"uniqueIndex:idx_rule_override_scope" json:"repo,omitempty"`
+	Channel   string    `gorm:"uniqueIndex:idx_rule_override_scope" json:"channel,omitempty"`
+	Disabled  bool      `json:"disabled"`
+	Severity  string    `gorm:"size:16" json:"severity,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// PathFilter holds a repo's gitignore-style path patterns. They are
+// combined with the global patterns: Exclude is appended to the global
+// excludes and a non-empty Include replaces the global includes.
+type PathFilter struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Repo      string    `gorm:"uniqueIndex" json:"repo"`
+	Include   []string  `gorm:"serializer:json" json:"include"`
+	Exclude   []string  `gorm:"serializer:json" json:"exclude"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
 func Migrate() error {
-	return DB.AutoMigrate(&Issue{}, &Suppression{})
+	return DB.AutoMigrate(&Issue{}, &Suppression{}, &RuleOverride{}, &PathFilter{})
+}
+
+// RuleOverridesFor returns the overrides that apply to a scan of repo or
+// channel, channel overrides first so a repo override for the same rule is
+// applied last.
+func RuleOverridesFor(repo, channel string) ([]RuleOverride, error) {
+	var out []RuleOverride
+	if repo == "" && channel == "" {
+		return out, nil
+	}
+	err := DB.Where("(repo <> '' AND repo = ?) OR (channel <> '' AND channel = ?)", repo, channel).
+		Order("repo, id").Find(&out).Error
+	return out, err
+}
+
+// BackfillSeverity sets the severity of issues stored before severities were
+// persisted, from the current severity of their finding type.
+func BackfillSeverity(byType map[string]string) error {
+	for t, sev := range byType {
+		if err := DB.Model(&Issue{}).Where("type = ? AND (severity = '' OR severity IS NULL)", t).Update("severity", sev).Error; err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -1,7 +1,14 @@
 "use client";
 
 import { useEffect, useState } from "react";
-import { Issue, fetchIssues, startScan, bulkScan } from "@/lib/api";
+import {
+  Issue,
+  RuleInfo,
+  fetchIssues,
+  fetchRules,
+  startScan,
+  bulkScan,
+} from "@/lib/api";
 import { TicketsTable } from "@/components/TicketsTable";
 import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card";
 import { Button } from "@/components/ui/button";
@@ -22,6 +29,7 @@ import { Textarea } from "@/components/ui/textarea";
 
 export default function Page() {
   const [issues, setIssues] = useState<Issue[] | null>(null);
+  const [rules, setRules] = useState<Record<string, RuleInfo>>({});
   const [err, setErr] = useState<string | null>(null);
   const [tab, setTab] = useState<string>("active");
   const [scanning, setScanning] = useState(false);
@@ -124,6 +132,10 @@ export default function Page() {
 
   useEffect(() => {
     load();
+    // rule docs are optional extras in the ticket view
+    fetchRules()
+      .then((c) => setRules(Object.fromEntries(c.rules.map((r) => [r.id, r]))))
+      .catch(() => setRules({}));
   }, []);
 
   const active = (issues || []).filter((i) => i.status !== "resolved");
@@ -384,7 +396,7 @@ export default function Page() {
                     <Skeleton className="h-8 w-full bg-zinc-800" />
                   </div>
                 ) : (
-                  <TicketsTable issues={active} onNotify={notify} />
+                  <TicketsTable issues={active} rules={rules} onNotify={notify} />
                 )}
               </CardContent>
             </Card>
@@ -401,7 +413,7 @@ export default function Page() {
                     <Skeleton className="h-8 w-full bg-zinc-800" />
                   </div>
                 ) : (
-                  <TicketsTable issues={resolved} onNotify={notify} />
+                  <TicketsTable issues={resolved} rules={rules} onNotify={notify} />
                 )}
               </CardContent>
             </Card>
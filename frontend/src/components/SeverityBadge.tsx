@@ -1,10 +1,18 @@
 "use client";
 
 import { Badge } from "@/components/ui/badge";
+import type { Severity } from "@/lib/api";
 
-type Props = { severity: "high" | "medium" | "low" };
+type Props = { severity: Severity };
 
 export function SeverityBadge({ severity }: Props) {
+  if (severity === "critical") {
+    return (
+      <Badge className="bg-fuchsia-900 text-fuchsia-100 border-fuchsia-800 hover:bg-fuchsia-800">
+        Critical
+      </Badge>
+    );
+  }
   if (severity === "high") {
     return (
       <Badge className="bg-red-900 text-red-100 border-red-800 hover:bg-red-800">
@@ -3,8 +3,8 @@
 import { useState } from "react";
 import {
   Issue,
+  RuleInfo,
   resolveIssue,
-  severityForType,
   ignoreIssue,
   bulkTicketsAction,
 } from "@/lib/api";
@@ -49,10 +49,11 @@ function EyeIcon(props: React.SVGProps<SVGSVGElement>) {
 
 type Props = {
   issues: Issue[];
+  rules?: Record<string, RuleInfo>;
   onNotify?: (msg: string, kind?: "success" | "error") => void;
 };
 
-export function TicketsTable({ issues, onNotify }: Props) {
+export function TicketsTable({ issues, rules, onNotify }: Props) {
   const [busyId, setBusyId] = useState<string | null>(null);
   const [confirmId, setConfirmId] = useState<string | null>(null);
   const [view, setView] = useState<Issue | null>(null);
@@ -158,9 +159,10 @@ export function TicketsTable({ issues, onNotify }: Props) {
         </TableHeader>
         <TableBody>
           {issues.map((t) => {
-            const sev = severityForType(t.type);
+            const sev = t.severity;
+            const file = t.file && t.line ? `${t.file}:${t.line}` : t.file;
             const location =
-              [t.repo, t.file].filter(Boolean).join(" / ") || "-";
+              [t.repo, file].filter(Boolean).join(" / ") || "-";
             return (
               <TableRow
                 key={t.id}
@@ -226,6 +228,14 @@ export function TicketsTable({ issues, onNotify }: Props) {
                               <span className="text-zinc-400">Severity:</span>
                               <SeverityBadge severity={sev} />
                             </div>
+                            {t.confidence ? (
+                              <div className="flex justify-between">
+                                <span className="text-zinc-400">Confidence:</span>
+                                <span className="text-white">
+                                  {Math.round(t.confidence * 100)}%
+                                </span>
+                              </div>
+                            ) : null}
                             <div className="flex justify-between">
                               <span className="text-zinc-400">Status:</span>
                               <span className="text-white capitalize">
@@ -235,6 +245,33 @@ export function TicketsTable({ issues, onNotify }: Props) {
                             <div></div>
                           </div>
 
+                          {rules?.[t.type] && (
+                            <div className="border-t border-zinc-800 pt-4">
+                              <h4 className="text-zinc-300 font-medium mb-3">
+                                Rule
+                              </h4>
+                              <div className="space-y-2 text-sm">
+                                <p className="text-white">
+                                  {rules[t.type].description}
+                                </p>
+                                <p className="text-zinc-400">
+                                  {rules[t.type].provider} ·{" "}
+                                  {rules[t.type].source}
+                                </p>
+                                {rules[t.type].remediation && (
+                                  <a
+                                    href={rules[t.type].remediation}
+                                    target="_blank"
+                                    rel="noopener noreferrer"
+                                    className="text-blue-400 hover:underline"
+                                  >
+                                    Remediation guide
+                                  </a>
+                                )}
+                              </div>
+                            </div>
+                          )}
+
                           {(t.repo || t.commit || t.channel || t.file) && (
                             <div className="border-t border-zinc-800 pt-4">
                               <h4 className="text-zinc-300 font-medium mb-3">
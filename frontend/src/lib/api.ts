@@ -1,11 +1,20 @@
+export type Severity = "critical" | "high" | "medium" | "low";
+
 export type Issue = {
   id: string;
   type: string;
+  severity: Severity;
+  confidence?: number;
   status: string;
   repo?: string;
   commit?: string;
   channel?: string;
   file?: string;
+  line?: number;
+  column?: number;
+  startOffset?: number;
+  endOffset?: number;
+  snippet?: string;
   createdAt?: string;
   updatedAt?: string;
 };
@@ -15,12 +24,48 @@ export type ScanResponse = {
   created: number;
   resolved?: number;
   duplicates?: number;
-  issues: { id: string; type: string }[];
+  allowlisted?: number;
+  disabled?: number;
+  skippedFiles?: number;
+  skipReason?: string;
+  issues: { id: string; type: string; severity?: Severity; confidence?: number }[];
   errors: string[];
 };
 
+export type RuleInfo = {
+  id: string;
+  description: string;
+  severity: Severity;
+  confidence?: number;
+  provider: string;
+  remediation?: string;
+  example?: string;
+  enabled: boolean;
+  source: "built-in" | "custom";
+};
+
+export type RuleCatalog = {
+  version: string;
+  rules: RuleInfo[];
+};
+
 const API_BASE = process.env.NEXT_PUBLIC_API_URL;
 
+export async function fetchRules(scope?: {
+  repo?: string;
+  channel?: string;
+}): Promise<RuleCatalog> {
+  const params = new URLSearchParams();
+  if (scope?.repo) params.set("repo", scope.repo);
+  if (scope?.channel) params.set("channel", scope.channel);
+  const qs = params.toString();
+  const res = await fetch(`${API_BASE}/rules${qs ? `?${qs}` : ""}`, {
+    cache: "no-store",
+  });
+  if (!res.ok) throw new Error(`Failed to fetch rules: ${res.status}`);
+  return res.json();
+}
+
 export async function fetchIssues(): Promise<Issue[]> {
   const res = await fetch(`${API_BASE}/tickets`, { cache: "no-store" });
   if (!res.ok) throw new Error(`Failed to fetch tickets: ${res.status}`);
@@ -74,8 +119,12 @@ export async function bulkScan(
     created: number;
     resolved: number;
     duplicates: number;
+    allowlisted?: number;
+    disabled?: number;
+    skipped?: string;
     error?: string;
   }>;
+  skipped?: number;
 }> {
   const res = await fetch(`${API_BASE}/scan/bulk`, {
     method: "POST",
@@ -101,10 +150,3 @@ export async function startScan(payload: {
   if (!res.ok) throw new Error(`Failed to scan: ${res.status}`);
   return res.json();
 }
-
-export function severityForType(type: string): "high" | "medium" | "low" {
-  const t = type.toLowerCase();
-  if (/(awsaccesskey|awssecretkey|stripe|jwt)/.test(t)) return "high";
-  if (/(github|slack|google)/.test(t)) return "medium";
-  return "low";
-}